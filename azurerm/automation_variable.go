@@ -0,0 +1,254 @@
+package azurerm
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+// The typed Automation Variable resources (e.g. `azurerm_automation_variable_int`) share everything
+// but the type of their `value`, which is encoded/decoded in the same way as `azurerm_automation_variable`
+
+func automationVariableTypedSchema(value *schema.Schema) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+
+		"resource_group_name": resourceGroupNameSchema(),
+
+		"account_name": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+
+		"value": value,
+
+		"encrypted": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+			ForceNew: true,
+		},
+
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	}
+}
+
+func automationVariableTypedCreateUpdate(d *schema.ResourceData, meta interface{}, variableType string) error {
+	client := meta.(*ArmClient).automationVariableClient
+	log.Printf("[INFO] preparing arguments for AzureRM Automation %s Variable creation.", variableType)
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+	accName := d.Get("account_name").(string)
+	description := d.Get("description").(string)
+	encrypted := d.Get("encrypted").(bool)
+
+	var input string
+	switch v := d.Get("value").(type) {
+	case int:
+		input = strconv.Itoa(v)
+	case bool:
+		input = strconv.FormatBool(v)
+	case string:
+		input = v
+	}
+
+	value, err := expandAzureRmAutomationVariableValue(variableType, input)
+	if err != nil {
+		return err
+	}
+
+	parameters := automation.VariableCreateOrUpdateParameters{
+		Name: &name,
+		VariableCreateOrUpdateProperties: &automation.VariableCreateOrUpdateProperties{
+			Value:       &value,
+			Description: &description,
+			IsEncrypted: &encrypted,
+		},
+	}
+
+	_, err = client.CreateOrUpdate(resGroup, accName, name, parameters)
+	if err != nil {
+		return fmt.Errorf("Error creating/updating Automation %s Variable %q (Account %q / Resource Group %q): %+v", variableType, name, accName, resGroup, err)
+	}
+
+	read, err := client.Get(resGroup, accName, name)
+	if err != nil {
+		return err
+	}
+
+	if read.ID == nil {
+		return fmt.Errorf("Cannot read Automation %s Variable '%s' (resource group %s) ID", variableType, name, resGroup)
+	}
+
+	d.SetId(*read.ID)
+
+	return automationVariableTypedRead(d, meta, variableType)
+}
+
+func automationVariableTypedRead(d *schema.ResourceData, meta interface{}, variableType string) error {
+	client := meta.(*ArmClient).automationVariableClient
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	accName := id.Path["automationAccounts"]
+	name := id.Path["variables"]
+
+	resp, err := client.Get(resGroup, accName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error making Read request on AzureRM Automation %s Variable '%s': %+v", variableType, name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGroup)
+	d.Set("account_name", accName)
+
+	if props := resp.VariableProperties; props != nil {
+		d.Set("description", props.Description)
+
+		encrypted := false
+		if v := props.IsEncrypted; v != nil {
+			encrypted = *v
+		}
+		d.Set("encrypted", encrypted)
+
+		// the value of an encrypted variable isn't returned from the API, so we keep what's in the config
+		if !encrypted && props.Value != nil {
+			if err := flattenAndSetAutomationVariableTypedValue(d, variableType, *props.Value); err != nil {
+				return fmt.Errorf("Error flattening the value of Automation %s Variable %q (Account %q / Resource Group %q): %+v", variableType, name, accName, resGroup, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// flattenAndSetAutomationVariableTypedValue sets `value` from the JSON-encoded value of a Variable, returning
+// an error if the Variable isn't of the expected type
+func flattenAndSetAutomationVariableTypedValue(d *schema.ResourceData, variableType string, input string) error {
+	actualType, value, err := flattenAzureRmAutomationVariableValue(input)
+	if err != nil {
+		return err
+	}
+
+	if actualType != variableType {
+		return fmt.Errorf("the Variable is a %s Variable rather than a %s Variable", actualType, variableType)
+	}
+
+	switch variableType {
+	case automationVariableTypeInteger:
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("Error parsing %q as an Integer: %+v", value, err)
+		}
+		d.Set("value", i)
+	case automationVariableTypeBoolean:
+		d.Set("value", value == "true")
+	default:
+		d.Set("value", value)
+	}
+
+	return nil
+}
+
+// automationVariableTypedDataSourceSchema returns the schema for the Data Source of the specified type of
+// Automation Variable - where only the value of a String Variable is sensitive
+func automationVariableTypedDataSourceSchema(variableType string) map[string]*schema.Schema {
+	valueType := schema.TypeString
+	switch variableType {
+	case automationVariableTypeBoolean:
+		valueType = schema.TypeBool
+	case automationVariableTypeInteger:
+		valueType = schema.TypeInt
+	}
+
+	return map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+
+		"resource_group_name": resourceGroupNameForDataSourceSchema(),
+
+		"account_name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+
+		"value": {
+			Type:      valueType,
+			Computed:  true,
+			Sensitive: variableType == automationVariableTypeString,
+		},
+
+		"encrypted": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+
+		"description": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func automationVariableTypedDataSourceRead(d *schema.ResourceData, meta interface{}, variableType string) error {
+	client := meta.(*ArmClient).automationVariableClient
+
+	resourceGroup := d.Get("resource_group_name").(string)
+	accName := d.Get("account_name").(string)
+	name := d.Get("name").(string)
+
+	resp, err := client.Get(resourceGroup, accName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			return fmt.Errorf("Error: Automation %s Variable %q (Account %q / Resource Group %q) was not found", variableType, name, accName, resourceGroup)
+		}
+		return fmt.Errorf("Error making Read request on Automation %s Variable %q (Account %q / Resource Group %q): %+v", variableType, name, accName, resourceGroup, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resourceGroup)
+	d.Set("account_name", accName)
+
+	if props := resp.VariableProperties; props != nil {
+		d.Set("description", props.Description)
+
+		encrypted := false
+		if v := props.IsEncrypted; v != nil {
+			encrypted = *v
+		}
+		d.Set("encrypted", encrypted)
+
+		// the value of an encrypted variable isn't returned from the API, so only its metadata is available
+		if !encrypted && props.Value != nil {
+			if err := flattenAndSetAutomationVariableTypedValue(d, variableType, *props.Value); err != nil {
+				return fmt.Errorf("Error flattening the value of Automation %s Variable %q (Account %q / Resource Group %q): %+v", variableType, name, accName, resourceGroup, err)
+			}
+		}
+	}
+
+	return nil
+}
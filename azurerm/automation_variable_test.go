@@ -0,0 +1,97 @@
+package azurerm
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestAzureRMAutomationVariableTypedValue_flatten(t *testing.T) {
+	cases := []struct {
+		Type        string
+		Input       string
+		Expected    interface{}
+		ExpectError bool
+	}{
+		{
+			Type:     automationVariableTypeString,
+			Input:    `"Hello, Terraform"`,
+			Expected: "Hello, Terraform",
+		},
+		{
+			Type:     automationVariableTypeInteger,
+			Input:    "1234",
+			Expected: 1234,
+		},
+		{
+			Type:     automationVariableTypeBoolean,
+			Input:    "true",
+			Expected: true,
+		},
+		{
+			Type:     automationVariableTypeDateTime,
+			Input:    `"\/Date(1514764800000)\/"`,
+			Expected: "2018-01-01T00:00:00Z",
+		},
+		{
+			// a String Variable whose value looks like an Integer is still a String
+			Type:     automationVariableTypeString,
+			Input:    `"1234"`,
+			Expected: "1234",
+		},
+		{
+			Type:        automationVariableTypeInteger,
+			Input:       `"Hello, Terraform"`,
+			ExpectError: true,
+		},
+		{
+			Type:        automationVariableTypeString,
+			Input:       "true",
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, automationVariableTypedDataSourceSchema(tc.Type), map[string]interface{}{})
+
+		err := flattenAndSetAutomationVariableTypedValue(d, tc.Type, tc.Input)
+		if tc.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected an error flattening %s as a %s Variable but didn't get one", tc.Input, tc.Type)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected no error flattening %s as a %s Variable but got: %+v", tc.Input, tc.Type, err)
+		}
+
+		if actual := d.Get("value"); actual != tc.Expected {
+			t.Fatalf("Expected %s to be flattened to %v but got %v", tc.Input, tc.Expected, actual)
+		}
+	}
+}
+
+func TestAzureRMAutomationVariableTypedDataSourceSchema_sensitive(t *testing.T) {
+	cases := []struct {
+		Type      string
+		ValueType schema.ValueType
+		Sensitive bool
+	}{
+		{Type: automationVariableTypeString, ValueType: schema.TypeString, Sensitive: true},
+		{Type: automationVariableTypeInteger, ValueType: schema.TypeInt, Sensitive: false},
+		{Type: automationVariableTypeBoolean, ValueType: schema.TypeBool, Sensitive: false},
+		{Type: automationVariableTypeDateTime, ValueType: schema.TypeString, Sensitive: false},
+	}
+
+	for _, tc := range cases {
+		value := automationVariableTypedDataSourceSchema(tc.Type)["value"]
+		if value.Type != tc.ValueType {
+			t.Fatalf("Expected the value of a %s Variable to be a %s but got a %s", tc.Type, tc.ValueType, value.Type)
+		}
+
+		if value.Sensitive != tc.Sensitive {
+			t.Fatalf("Expected the value of a %s Variable to be sensitive to be %t but got %t", tc.Type, tc.Sensitive, value.Sensitive)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package azurerm
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httputil"
@@ -61,6 +62,15 @@ type ArmClient struct {
 
 	StopContext context.Context
 
+	// defaultTags are merged into the tags of the resources which support them, see `mergeDefaultTags`
+	defaultTags map[string]interface{}
+
+	// hiddenTagPrefix is the prefix of the tags added by Azure, which are ignored by the resources which support `defaultTags`
+	hiddenTagPrefix string
+
+	// warnOnOpenSecurityRules logs a warning for each Security Rule which allows inbound traffic from the Internet to any port
+	warnOnOpenSecurityRules bool
+
 	availSetClient         compute.AvailabilitySetsClient
 	usageOpsClient         compute.UsageClient
 	vmExtensionImageClient compute.VirtualMachineExtensionImagesClient
@@ -70,13 +80,17 @@ type ArmClient struct {
 	vmClient               compute.VirtualMachinesClient
 	imageClient            compute.ImagesClient
 
-	diskClient                 disk.DisksClient
-	snapshotsClient            disk.SnapshotsClient
-	cosmosDBClient             cosmosdb.DatabaseAccountsClient
-	automationAccountClient    automation.AccountClient
-	automationRunbookClient    automation.RunbookClient
-	automationCredentialClient automation.CredentialClient
-	automationScheduleClient   automation.ScheduleClient
+	diskClient                               disk.DisksClient
+	snapshotsClient                          disk.SnapshotsClient
+	cosmosDBClient                           cosmosdb.DatabaseAccountsClient
+	automationAccountClient                  automation.AccountClient
+	automationAgentRegistrationClient        automation.AgentRegistrationInformationClient
+	automationRunbookClient                  automation.RunbookClient
+	automationCredentialClient               automation.CredentialClient
+	automationHybridRunbookWorkerGroupClient automation.HybridRunbookWorkerGroupClient
+	automationScheduleClient                 automation.ScheduleClient
+	automationVariableClient                 automation.VariableClient
+	automationWebhookClient                  automation.WebhookClient
 
 	applicationGatewayClient     network.ApplicationGatewaysClient
 	ifaceClient                  network.InterfacesClient
@@ -100,9 +114,11 @@ type ArmClient struct {
 	cdnProfilesClient  cdn.ProfilesClient
 	cdnEndpointsClient cdn.EndpointsClient
 
-	containerRegistryClient containerregistry.RegistriesClient
-	containerServicesClient containerservice.ContainerServicesClient
-	containerGroupsClient   containerinstance.ContainerGroupsClient
+	containerRegistryClient             containerregistry.RegistriesClient
+	containerRegistryReplicationsClient containerregistry.ReplicationsClient
+	containerRegistryWebhooksClient     containerregistry.WebhooksClient
+	containerServicesClient             containerservice.ContainerServicesClient
+	containerGroupsClient               containerinstance.ContainerGroupsClient
 
 	eventGridTopicsClient       eventgrid.TopicsClient
 	eventHubClient              eventhub.EventHubsClient
@@ -208,6 +224,46 @@ func withRequestLogging() autorest.SendDecorator {
 	}
 }
 
+// withRequestTimeout cancels any request which hasn't completed within the specified timeout,
+// allowing the configured Read timeout for a resource to be honoured for single requests
+func withRequestTimeout(timeout time.Duration) autorest.SendDecorator {
+	return func(s autorest.Sender) autorest.Sender {
+		return autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+			ctx, cancel := context.WithCancel(r.Context())
+			timer := time.AfterFunc(timeout, cancel)
+			release := func() {
+				timer.Stop()
+				cancel()
+			}
+
+			resp, err := s.Do(r.WithContext(ctx))
+			if err != nil || resp == nil || resp.Body == nil {
+				release()
+				return resp, err
+			}
+
+			// the body is read after the request returns, so the context can only be released once it's closed
+			resp.Body = &cancelOnCloseBody{
+				ReadCloser: resp.Body,
+				cancel:     release,
+			}
+			return resp, err
+		})
+	}
+}
+
+// cancelOnCloseBody is a response body which releases the request's context when it's closed
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel func()
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 func setUserAgent(client *autorest.Client) {
 	tfVersion := fmt.Sprintf("HashiCorp-Terraform-v%s", terraform.VersionString())
 
@@ -379,6 +435,20 @@ func getArmClient(c *authentication.Config) (*ArmClient, error) {
 	crc.SkipResourceProviderRegistration = c.SkipProviderRegistration
 	client.containerRegistryClient = crc
 
+	crrc := containerregistry.NewReplicationsClientWithBaseURI(endpoint, c.SubscriptionID)
+	setUserAgent(&crrc.Client)
+	crrc.Authorizer = auth
+	crrc.Sender = sender
+	crrc.SkipResourceProviderRegistration = c.SkipProviderRegistration
+	client.containerRegistryReplicationsClient = crrc
+
+	crwc := containerregistry.NewWebhooksClientWithBaseURI(endpoint, c.SubscriptionID)
+	setUserAgent(&crwc.Client)
+	crwc.Authorizer = auth
+	crwc.Sender = sender
+	crwc.SkipResourceProviderRegistration = c.SkipProviderRegistration
+	client.containerRegistryWebhooksClient = crwc
+
 	csc := containerservice.NewContainerServicesClientWithBaseURI(endpoint, c.SubscriptionID)
 	setUserAgent(&csc.Client)
 	csc.Authorizer = auth
@@ -561,6 +631,13 @@ func (c *ArmClient) registerAutomationClients(endpoint, subscriptionId string, a
 	accountClient.SkipResourceProviderRegistration = c.skipProviderRegistration
 	c.automationAccountClient = accountClient
 
+	agentRegistrationClient := automation.NewAgentRegistrationInformationClientWithBaseURI(endpoint, subscriptionId)
+	setUserAgent(&agentRegistrationClient.Client)
+	agentRegistrationClient.Authorizer = auth
+	agentRegistrationClient.Sender = sender
+	agentRegistrationClient.SkipResourceProviderRegistration = c.skipProviderRegistration
+	c.automationAgentRegistrationClient = agentRegistrationClient
+
 	credentialClient := automation.NewCredentialClientWithBaseURI(endpoint, subscriptionId)
 	setUserAgent(&credentialClient.Client)
 	credentialClient.Authorizer = auth
@@ -568,6 +645,13 @@ func (c *ArmClient) registerAutomationClients(endpoint, subscriptionId string, a
 	credentialClient.SkipResourceProviderRegistration = c.skipProviderRegistration
 	c.automationCredentialClient = credentialClient
 
+	hybridRunbookWorkerGroupClient := automation.NewHybridRunbookWorkerGroupClientWithBaseURI(endpoint, subscriptionId)
+	setUserAgent(&hybridRunbookWorkerGroupClient.Client)
+	hybridRunbookWorkerGroupClient.Authorizer = auth
+	hybridRunbookWorkerGroupClient.Sender = sender
+	hybridRunbookWorkerGroupClient.SkipResourceProviderRegistration = c.skipProviderRegistration
+	c.automationHybridRunbookWorkerGroupClient = hybridRunbookWorkerGroupClient
+
 	runbookClient := automation.NewRunbookClientWithBaseURI(endpoint, subscriptionId)
 	setUserAgent(&runbookClient.Client)
 	runbookClient.Authorizer = auth
@@ -581,6 +665,20 @@ func (c *ArmClient) registerAutomationClients(endpoint, subscriptionId string, a
 	scheduleClient.Sender = sender
 	scheduleClient.SkipResourceProviderRegistration = c.skipProviderRegistration
 	c.automationScheduleClient = scheduleClient
+
+	variableClient := automation.NewVariableClientWithBaseURI(endpoint, subscriptionId)
+	setUserAgent(&variableClient.Client)
+	variableClient.Authorizer = auth
+	variableClient.Sender = sender
+	variableClient.SkipResourceProviderRegistration = c.skipProviderRegistration
+	c.automationVariableClient = variableClient
+
+	webhookClient := automation.NewWebhookClientWithBaseURI(endpoint, subscriptionId)
+	setUserAgent(&webhookClient.Client)
+	webhookClient.Authorizer = auth
+	webhookClient.Sender = sender
+	webhookClient.SkipResourceProviderRegistration = c.skipProviderRegistration
+	c.automationWebhookClient = webhookClient
 }
 
 func (c *ArmClient) registerAuthentication(endpoint, graphEndpoint, subscriptionId, tenantId string, auth, graphAuth autorest.Authorizer, sender autorest.Sender) {
@@ -0,0 +1,60 @@
+package azurerm
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestWithRequestTimeout_cancelsWhenBodyIsClosed(t *testing.T) {
+	var request *http.Request
+	sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		request = r
+		return testAzureResponse(r, http.StatusOK, `{}`), nil
+	})
+
+	r, err := http.NewRequest(http.MethodGet, "https://management.azure.com", nil)
+	if err != nil {
+		t.Fatalf("Error building the request: %+v", err)
+	}
+
+	resp, err := withRequestTimeout(time.Hour)(sender).Do(r)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %+v", err)
+	}
+
+	// the body hasn't been read yet, so the request mustn't have been cancelled
+	if err := request.Context().Err(); err != nil {
+		t.Fatalf("Expected the request not to be cancelled before the body is closed but got: %+v", err)
+	}
+
+	if _, err := ioutil.ReadAll(resp.Body); err != nil {
+		t.Fatalf("Error reading the body: %+v", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		t.Fatalf("Error closing the body: %+v", err)
+	}
+
+	if err := request.Context().Err(); err == nil {
+		t.Fatalf("Expected the request's context to be released once the body is closed")
+	}
+}
+
+func TestWithRequestTimeout_cancelsAfterTimeout(t *testing.T) {
+	sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	})
+
+	r, err := http.NewRequest(http.MethodGet, "https://management.azure.com", nil)
+	if err != nil {
+		t.Fatalf("Error building the request: %+v", err)
+	}
+
+	if _, err := withRequestTimeout(10 * time.Millisecond)(sender).Do(r); err == nil {
+		t.Fatalf("Expected the request to be cancelled once the timeout elapsed")
+	}
+}
@@ -0,0 +1,111 @@
+package azurerm
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func dataSourceArmAutomationAccount() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceArmAutomationAccountRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"resource_group_name": resourceGroupNameForDataSourceSchema(),
+
+			"location": locationForDataSourceSchema(),
+
+			"sku": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"dsc_server_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"dsc_primary_access_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"dsc_secondary_access_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"tags": tagsForDataSourceSchema(),
+		},
+	}
+}
+
+func dataSourceArmAutomationAccountRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationAccountClient
+
+	resourceGroup := d.Get("resource_group_name").(string)
+	name := d.Get("name").(string)
+
+	resp, err := client.Get(resourceGroup, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			return fmt.Errorf("Error: Automation Account %q (Resource Group %q) was not found", name, resourceGroup)
+		}
+		return fmt.Errorf("Error making Read request on Automation Account %q (Resource Group %q): %+v", name, resourceGroup, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resourceGroup)
+	if location := resp.Location; location != nil {
+		d.Set("location", azureRMNormalizeLocation(*location))
+	}
+
+	if props := resp.AccountProperties; props != nil {
+		flattenAndSetSku(d, props.Sku)
+	}
+
+	flattenAndSetTags(d, resp.Tags)
+
+	registrationClient := meta.(*ArmClient).automationAgentRegistrationClient
+	ctx, cancel := context.WithTimeout(meta.(*ArmClient).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	var registration automation.AgentRegistration
+	err = retryOn429(ctx, func() (*http.Response, error) {
+		var err error
+		registration, err = registrationClient.Get(resourceGroup, name)
+		return registration.Response.Response, err
+	})
+	if err != nil {
+		log.Printf("[WARN] Unable to retrieve the Agent Registration Information for Automation Account %q (Resource Group %q): %+v", name, resourceGroup, err)
+		d.Set("dsc_server_endpoint", "")
+		d.Set("dsc_primary_access_key", "")
+		d.Set("dsc_secondary_access_key", "")
+		return nil
+	}
+
+	flattenAndSetAutomationAccountAgentRegistration(d, registration)
+
+	return nil
+}
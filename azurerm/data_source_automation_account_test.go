@@ -0,0 +1,78 @@
+package azurerm
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceAzureRMAutomationAccount_basic(t *testing.T) {
+	dataSourceName := "data.azurerm_automation_account.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAzureRMAutomationAccount_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "location"),
+					resource.TestCheckResourceAttr(dataSourceName, "sku.0.name", "Basic"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "dsc_server_endpoint"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "dsc_primary_access_key"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "dsc_secondary_access_key"),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.%", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.hello", "world"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAzureRMAutomationAccount_notFound(t *testing.T) {
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceAzureRMAutomationAccount_notFound(ri, location),
+				ExpectError: regexp.MustCompile("was not found"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAzureRMAutomationAccount_basic(rInt int, location string) string {
+	template := testAccAzureRMAutomationAccount_complete(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+data "azurerm_automation_account" "test" {
+  name                = "${azurerm_automation_account.test.name}"
+  resource_group_name = "${azurerm_automation_account.test.resource_group_name}"
+}
+`, template)
+}
+
+func testAccDataSourceAzureRMAutomationAccount_notFound(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+data "azurerm_automation_account" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+}
+`, rInt, location, rInt)
+}
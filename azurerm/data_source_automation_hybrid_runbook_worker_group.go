@@ -0,0 +1,107 @@
+package azurerm
+
+import (
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func dataSourceArmAutomationHybridRunbookWorkerGroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceArmAutomationHybridRunbookWorkerGroupRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"resource_group_name": resourceGroupNameForDataSourceSchema(),
+
+			"account_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"credential_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"hybrid_runbook_worker": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceArmAutomationHybridRunbookWorkerGroupRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationHybridRunbookWorkerGroupClient
+
+	resourceGroup := d.Get("resource_group_name").(string)
+	accName := d.Get("account_name").(string)
+	name := d.Get("name").(string)
+
+	resp, err := client.Get(resourceGroup, accName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			return fmt.Errorf("Error: Hybrid Runbook Worker Group %q (Account %q / Resource Group %q) was not found", name, accName, resourceGroup)
+		}
+		return fmt.Errorf("Error making Read request on Hybrid Runbook Worker Group %q (Account %q / Resource Group %q): %+v", name, accName, resourceGroup, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resourceGroup)
+	d.Set("account_name", accName)
+
+	credentialName := ""
+	if credential := resp.Credential; credential != nil && credential.Name != nil {
+		credentialName = *credential.Name
+	}
+	d.Set("credential_name", credentialName)
+
+	if err := d.Set("hybrid_runbook_worker", flattenAzureRmAutomationHybridRunbookWorkers(resp.HybridRunbookWorkers)); err != nil {
+		return fmt.Errorf("Error setting `hybrid_runbook_worker`: %+v", err)
+	}
+
+	return nil
+}
+
+func flattenAzureRmAutomationHybridRunbookWorkers(input *[]automation.HybridRunbookWorker) []interface{} {
+	results := make([]interface{}, 0)
+	if input == nil {
+		return results
+	}
+
+	for _, worker := range *input {
+		result := make(map[string]interface{})
+
+		if v := worker.Name; v != nil {
+			result["name"] = *v
+		}
+		if v := worker.IP; v != nil {
+			result["ip_address"] = *v
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
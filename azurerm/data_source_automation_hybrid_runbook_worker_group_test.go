@@ -0,0 +1,72 @@
+package azurerm
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func TestAzureRMAutomationHybridRunbookWorkers_flatten(t *testing.T) {
+	workers := []automation.HybridRunbookWorker{
+		{
+			Name: utils.String("worker1"),
+			IP:   utils.String("10.0.0.4"),
+		},
+		{
+			Name: utils.String("worker2"),
+		},
+	}
+
+	results := flattenAzureRmAutomationHybridRunbookWorkers(&workers)
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 Hybrid Runbook Workers but got %d", len(results))
+	}
+
+	first := results[0].(map[string]interface{})
+	if first["name"] != "worker1" || first["ip_address"] != "10.0.0.4" {
+		t.Fatalf("Unexpected first Hybrid Runbook Worker: %+v", first)
+	}
+
+	second := results[1].(map[string]interface{})
+	if _, ok := second["ip_address"]; ok {
+		t.Fatalf("Expected no IP Address for the second Hybrid Runbook Worker: %+v", second)
+	}
+
+	if results := flattenAzureRmAutomationHybridRunbookWorkers(nil); len(results) != 0 {
+		t.Fatalf("Expected no Hybrid Runbook Workers when flattening nil but got %d", len(results))
+	}
+}
+
+func TestAccDataSourceAzureRMAutomationHybridRunbookWorkerGroup_notFound(t *testing.T) {
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceAzureRMAutomationHybridRunbookWorkerGroup_notFound(ri, location),
+				ExpectError: regexp.MustCompile("was not found"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAzureRMAutomationHybridRunbookWorkerGroup_notFound(rInt int, location string) string {
+	template := testAccAzureRMAutomationAccount_basic(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+data "azurerm_automation_hybrid_runbook_worker_group" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_automation_account.test.resource_group_name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+}
+`, template, rInt)
+}
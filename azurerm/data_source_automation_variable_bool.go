@@ -0,0 +1,16 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceArmAutomationVariableBool() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceArmAutomationVariableBoolRead,
+		Schema: automationVariableTypedDataSourceSchema(automationVariableTypeBoolean),
+	}
+}
+
+func dataSourceArmAutomationVariableBoolRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedDataSourceRead(d, meta, automationVariableTypeBoolean)
+}
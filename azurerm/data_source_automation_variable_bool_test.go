@@ -0,0 +1,52 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceAzureRMAutomationVariableBool_basic(t *testing.T) {
+	dataSourceName := "data.azurerm_automation_variable_bool.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAzureRMAutomationVariableBool_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "value", "true"),
+					resource.TestCheckResourceAttr(dataSourceName, "encrypted", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "description", "This is a test variable for terraform acceptance test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAzureRMAutomationVariableBool_basic(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_bool" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = true
+  description         = "This is a test variable for terraform acceptance test"
+}
+
+data "azurerm_automation_variable_bool" "test" {
+  name                = "${azurerm_automation_variable_bool.test.name}"
+  resource_group_name = "${azurerm_automation_variable_bool.test.resource_group_name}"
+  account_name        = "${azurerm_automation_variable_bool.test.account_name}"
+}
+`, template, rInt)
+}
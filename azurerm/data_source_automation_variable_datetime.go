@@ -0,0 +1,16 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceArmAutomationVariableDateTime() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceArmAutomationVariableDateTimeRead,
+		Schema: automationVariableTypedDataSourceSchema(automationVariableTypeDateTime),
+	}
+}
+
+func dataSourceArmAutomationVariableDateTimeRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedDataSourceRead(d, meta, automationVariableTypeDateTime)
+}
@@ -0,0 +1,16 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceArmAutomationVariableInt() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceArmAutomationVariableIntRead,
+		Schema: automationVariableTypedDataSourceSchema(automationVariableTypeInteger),
+	}
+}
+
+func dataSourceArmAutomationVariableIntRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedDataSourceRead(d, meta, automationVariableTypeInteger)
+}
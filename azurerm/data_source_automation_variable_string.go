@@ -0,0 +1,16 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceArmAutomationVariableString() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourceArmAutomationVariableStringRead,
+		Schema: automationVariableTypedDataSourceSchema(automationVariableTypeString),
+	}
+}
+
+func dataSourceArmAutomationVariableStringRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedDataSourceRead(d, meta, automationVariableTypeString)
+}
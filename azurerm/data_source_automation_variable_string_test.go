@@ -0,0 +1,93 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccDataSourceAzureRMAutomationVariableString_basic(t *testing.T) {
+	dataSourceName := "data.azurerm_automation_variable_string.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAzureRMAutomationVariableString_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "id"),
+					resource.TestCheckResourceAttr(dataSourceName, "value", "Hello, Terraform"),
+					resource.TestCheckResourceAttr(dataSourceName, "encrypted", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "description", "This is a test variable for terraform acceptance test"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAzureRMAutomationVariableString_encrypted(t *testing.T) {
+	dataSourceName := "data.azurerm_automation_variable_string.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAzureRMAutomationVariableString_encrypted(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "encrypted", "true"),
+					resource.TestCheckNoResourceAttr(dataSourceName, "value"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAzureRMAutomationVariableString_basic(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_string" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "Hello, Terraform"
+  description         = "This is a test variable for terraform acceptance test"
+}
+
+data "azurerm_automation_variable_string" "test" {
+  name                = "${azurerm_automation_variable_string.test.name}"
+  resource_group_name = "${azurerm_automation_variable_string.test.resource_group_name}"
+  account_name        = "${azurerm_automation_variable_string.test.account_name}"
+}
+`, template, rInt)
+}
+
+func testAccDataSourceAzureRMAutomationVariableString_encrypted(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_string" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "s3cr3t"
+  encrypted           = true
+}
+
+data "azurerm_automation_variable_string" "test" {
+  name                = "${azurerm_automation_variable_string.test.name}"
+  resource_group_name = "${azurerm_automation_variable_string.test.resource_group_name}"
+  account_name        = "${azurerm_automation_variable_string.test.account_name}"
+}
+`, template, rInt)
+}
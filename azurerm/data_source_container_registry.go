@@ -0,0 +1,249 @@
+package azurerm
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Azure/azure-sdk-for-go/arm/containerregistry"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/response"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func dataSourceArmContainerRegistry() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceArmContainerRegistryRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+
+			"resource_group_name": resourceGroupNameForDataSourceSchema(),
+
+			"location": locationForDataSourceSchema(),
+
+			"sku": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"admin_enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"storage_account_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"login_server": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"admin_username": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"admin_password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"webhook": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"service_uri": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"actions": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+
+						"scope": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+
+			"tags": tagsForDataSourceSchema(),
+		},
+	}
+}
+
+func dataSourceArmContainerRegistryRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).containerRegistryClient
+
+	resourceGroup := d.Get("resource_group_name").(string)
+	name := d.Get("name").(string)
+
+	resp, err := client.Get(resourceGroup, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			return fmt.Errorf("Error: Container Registry %q (Resource Group %q) was not found", name, resourceGroup)
+		}
+		return fmt.Errorf("Error making Read request on Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+	}
+
+	d.SetId(*resp.ID)
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resourceGroup)
+	if location := resp.Location; location != nil {
+		d.Set("location", azureRMNormalizeLocation(*location))
+	}
+
+	if sku := resp.Sku; sku != nil {
+		d.Set("sku", string(sku.Tier))
+	}
+
+	if props := resp.RegistryProperties; props != nil {
+		d.Set("admin_enabled", props.AdminUserEnabled)
+		d.Set("login_server", props.LoginServer)
+
+		if account := props.StorageAccount; account != nil {
+			d.Set("storage_account_id", account.ID)
+		}
+
+		if props.AdminUserEnabled != nil && *props.AdminUserEnabled {
+			ctx, cancel := context.WithTimeout(meta.(*ArmClient).StopContext, d.Timeout(schema.TimeoutRead))
+			defer cancel()
+
+			credsResp, err := listContainerRegistryCredentials(ctx, client, resourceGroup, name)
+			if err != nil {
+				return fmt.Errorf("Error retrieving Credentials for Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+			}
+
+			if credsResp != nil {
+				d.Set("admin_username", credsResp.Username)
+				if passwords := credsResp.Passwords; passwords != nil {
+					for _, v := range *passwords {
+						if v.Name == containerregistry.Password {
+							d.Set("admin_password", v.Value)
+						}
+					}
+				}
+			} else {
+				d.Set("admin_username", "")
+				d.Set("admin_password", "")
+			}
+		} else {
+			d.Set("admin_username", "")
+			d.Set("admin_password", "")
+		}
+	}
+
+	webhooksClient := meta.(*ArmClient).containerRegistryWebhooksClient
+	flattenedWebhooks, err := retrieveContainerRegistryDataSourceWebhooks(webhooksClient, resourceGroup, name)
+	if err != nil {
+		return err
+	}
+
+	if err := d.Set("webhook", flattenedWebhooks); err != nil {
+		return fmt.Errorf("Error setting `webhook`: %+v", err)
+	}
+
+	flattenAndSetTags(d, resp.Tags)
+
+	return nil
+}
+
+// retrieveContainerRegistryDataSourceWebhooks lists every page of Webhooks for the Container Registry, along
+// with their Callback Config - which is skipped when the caller isn't allowed to retrieve it
+func retrieveContainerRegistryDataSourceWebhooks(client containerregistry.WebhooksClient, resourceGroup string, registryName string) ([]interface{}, error) {
+	webhooks := make([]containerregistry.Webhook, 0)
+
+	resp, err := client.List(resourceGroup, registryName)
+	if err != nil {
+		return nil, fmt.Errorf("Error listing Webhooks for Container Registry %q (Resource Group %q): %+v", registryName, resourceGroup, err)
+	}
+	if resp.Value != nil {
+		webhooks = append(webhooks, *resp.Value...)
+	}
+
+	for resp.NextLink != nil && *resp.NextLink != "" {
+		resp, err = client.ListNextResults(resp)
+		if err != nil {
+			return nil, fmt.Errorf("Error listing the next page of Webhooks for Container Registry %q (Resource Group %q): %+v", registryName, resourceGroup, err)
+		}
+		if resp.Value != nil {
+			webhooks = append(webhooks, *resp.Value...)
+		}
+	}
+
+	flattened := make([]interface{}, 0)
+	for _, webhook := range webhooks {
+		if webhook.Name == nil {
+			continue
+		}
+
+		// the Service URI is only available via the Callback Config - the Custom Headers are omitted since they may contain secrets
+		callbackConfig, err := client.GetCallbackConfig(resourceGroup, registryName, *webhook.Name)
+		if err != nil {
+			if !response.WasForbidden(callbackConfig.Response.Response) {
+				return nil, fmt.Errorf("Error retrieving the Callback Config for Container Registry Webhook %q (Registry %q / Resource Group %q): %+v", *webhook.Name, registryName, resourceGroup, err)
+			}
+
+			log.Printf("[DEBUG] Not permitted to retrieve the Callback Config for Container Registry Webhook %q (Registry %q / Resource Group %q) - `service_uri` will be empty", *webhook.Name, registryName, resourceGroup)
+			callbackConfig = containerregistry.CallbackConfig{}
+		}
+
+		flattened = append(flattened, flattenContainerRegistryDataSourceWebhook(webhook, callbackConfig))
+	}
+
+	return flattened, nil
+}
+
+func flattenContainerRegistryDataSourceWebhook(webhook containerregistry.Webhook, callbackConfig containerregistry.CallbackConfig) map[string]interface{} {
+	result := map[string]interface{}{
+		"name":        "",
+		"service_uri": "",
+		"scope":       "",
+		"status":      "",
+	}
+
+	if webhook.Name != nil {
+		result["name"] = *webhook.Name
+	}
+
+	if callbackConfig.ServiceURI != nil {
+		result["service_uri"] = *callbackConfig.ServiceURI
+	}
+
+	if props := webhook.WebhookProperties; props != nil {
+		if props.Scope != nil {
+			result["scope"] = *props.Scope
+		}
+		result["status"] = string(props.Status)
+		result["actions"] = flattenContainerRegistryWebhookActions(props.Actions)
+	} else {
+		result["actions"] = flattenContainerRegistryWebhookActions(nil)
+	}
+
+	return result
+}
@@ -0,0 +1,257 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/arm/containerregistry"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func TestAccDataSourceAzureRMContainerRegistry_basic(t *testing.T) {
+	dataSourceName := "data.azurerm_container_registry.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMContainerRegistryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAzureRMContainerRegistry_basic(ri, location, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dataSourceName, "location"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "login_server"),
+					resource.TestCheckResourceAttr(dataSourceName, "sku", "Basic"),
+					resource.TestCheckResourceAttr(dataSourceName, "admin_enabled", "false"),
+					resource.TestCheckResourceAttr(dataSourceName, "admin_username", ""),
+					resource.TestCheckResourceAttr(dataSourceName, "admin_password", ""),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.%", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAzureRMContainerRegistry_adminEnabled(t *testing.T) {
+	dataSourceName := "data.azurerm_container_registry.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMContainerRegistryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAzureRMContainerRegistry_basic(ri, location, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "admin_enabled", "true"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "admin_username"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "admin_password"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccDataSourceAzureRMContainerRegistry_webhook(t *testing.T) {
+	dataSourceName := "data.azurerm_container_registry.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMContainerRegistryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceAzureRMContainerRegistry_webhook(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "webhook.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "webhook.0.name", fmt.Sprintf("testaccwebhook%d", ri)),
+					resource.TestCheckResourceAttr(dataSourceName, "webhook.0.service_uri", "https://mywebhookreceiver.example/mytag"),
+					resource.TestCheckResourceAttr(dataSourceName, "webhook.0.actions.#", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "webhook.0.scope", "mytag:*"),
+					resource.TestCheckResourceAttr(dataSourceName, "webhook.0.status", "enabled"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceAzureRMContainerRegistry_flattenWebhook(t *testing.T) {
+	webhook := containerregistry.Webhook{
+		Name: utils.String("webhook1"),
+		WebhookProperties: &containerregistry.WebhookProperties{
+			Status:  containerregistry.Disabled,
+			Scope:   utils.String("mytag:*"),
+			Actions: &[]containerregistry.WebhookAction{containerregistry.Push, containerregistry.Delete},
+		},
+	}
+	callbackConfig := containerregistry.CallbackConfig{
+		ServiceURI: utils.String("https://mywebhookreceiver.example/mytag"),
+		CustomHeaders: &map[string]*string{
+			"Authorization": utils.String("Bearer secret"),
+		},
+	}
+
+	result := flattenContainerRegistryDataSourceWebhook(webhook, callbackConfig)
+
+	expected := map[string]string{
+		"name":        "webhook1",
+		"service_uri": "https://mywebhookreceiver.example/mytag",
+		"scope":       "mytag:*",
+		"status":      "disabled",
+	}
+	for k, v := range expected {
+		if actual := result[k].(string); actual != v {
+			t.Fatalf("Expected %q to be %q but got %q", k, v, actual)
+		}
+	}
+
+	actions := result["actions"].(*schema.Set)
+	if actions.Len() != 2 || !actions.Contains("push") || !actions.Contains("delete") {
+		t.Fatalf("Expected the actions to be `push` and `delete` but got %+v", actions.List())
+	}
+
+	if _, ok := result["custom_headers"]; ok {
+		t.Fatalf("Expected the Custom Headers not to be exposed")
+	}
+}
+
+func TestDataSourceAzureRMContainerRegistry_retrieveWebhooks(t *testing.T) {
+	baseURI := "https://management.azure.com/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.ContainerRegistry/registries/acctestreg/webhooks"
+
+	client := containerregistry.NewWebhooksClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	client.Sender = autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		statusCode := http.StatusOK
+		var body string
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/first/getCallbackConfig"):
+			body = `{"serviceUri": "https://mywebhookreceiver.example/first"}`
+		case strings.HasSuffix(r.URL.Path, "/second/getCallbackConfig"):
+			statusCode = http.StatusForbidden
+			body = `{"error": {"code": "AuthorizationFailed", "message": "not permitted"}}`
+		case r.URL.Query().Get("page") == "2":
+			body = `{"value": [{"name": "second", "properties": {"status": "enabled", "scope": "", "actions": ["push"]}}]}`
+		default:
+			body = fmt.Sprintf(`{"value": [{"name": "first", "properties": {"status": "enabled", "scope": "", "actions": ["push"]}}], "nextLink": "%s?page=2"}`, baseURI)
+		}
+
+		return testAzureResponse(r, statusCode, body), nil
+	})
+
+	webhooks, err := retrieveContainerRegistryDataSourceWebhooks(client, "acctestRG", "acctestreg")
+	if err != nil {
+		t.Fatalf("Expected no error but got: %+v", err)
+	}
+
+	// the second Webhook is on the second page, and its Callback Config can't be retrieved
+	expected := map[string]string{
+		"first":  "https://mywebhookreceiver.example/first",
+		"second": "",
+	}
+	if len(webhooks) != len(expected) {
+		t.Fatalf("Expected %d Webhooks but got %d: %+v", len(expected), len(webhooks), webhooks)
+	}
+	for _, raw := range webhooks {
+		webhook := raw.(map[string]interface{})
+		name := webhook["name"].(string)
+		if actual := webhook["service_uri"].(string); actual != expected[name] {
+			t.Fatalf("Expected the `service_uri` of %q to be %q but got %q", name, expected[name], actual)
+		}
+	}
+}
+
+func TestAccDataSourceAzureRMContainerRegistry_notFound(t *testing.T) {
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccDataSourceAzureRMContainerRegistry_notFound(ri, location),
+				ExpectError: regexp.MustCompile("was not found"),
+			},
+		},
+	})
+}
+
+func testAccDataSourceAzureRMContainerRegistry_basic(rInt int, location string, adminEnabled bool) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "testAccRg-%d"
+  location = "%s"
+}
+
+resource "azurerm_container_registry" "test" {
+  name                = "testacccr%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  location            = "${azurerm_resource_group.test.location}"
+  sku                 = "Basic"
+  admin_enabled       = %t
+}
+
+data "azurerm_container_registry" "test" {
+  name                = "${azurerm_container_registry.test.name}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+}
+`, rInt, location, rInt, adminEnabled)
+}
+
+func testAccDataSourceAzureRMContainerRegistry_webhook(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "testAccRg-%d"
+  location = "%s"
+}
+
+resource "azurerm_container_registry" "test" {
+  name                = "testacccr%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  location            = "${azurerm_resource_group.test.location}"
+  sku                 = "Standard"
+}
+
+resource "azurerm_container_registry_webhook" "test" {
+  name                = "testaccwebhook%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  registry_name       = "${azurerm_container_registry.test.name}"
+  location            = "${azurerm_resource_group.test.location}"
+  service_uri         = "https://mywebhookreceiver.example/mytag"
+  scope               = "mytag:*"
+  actions             = ["push"]
+}
+
+data "azurerm_container_registry" "test" {
+  name                = "${azurerm_container_registry.test.name}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  depends_on          = ["azurerm_container_registry_webhook.test"]
+}
+`, rInt, location, rInt, rInt)
+}
+
+func testAccDataSourceAzureRMContainerRegistry_notFound(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "testAccRg-%d"
+  location = "%s"
+}
+
+data "azurerm_container_registry" "test" {
+  name                = "testacccr%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+}
+`, rInt, location, rInt)
+}
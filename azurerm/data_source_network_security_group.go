@@ -23,59 +23,13 @@ func dataSourceArmNetworkSecurityGroup() *schema.Resource {
 			"security_rule": {
 				Type:     schema.TypeList,
 				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"description": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"protocol": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"source_port_range": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"destination_port_range": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"source_address_prefix": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"destination_address_prefix": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"access": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-
-						"priority": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-
-						"direction": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-					},
-				},
+				Elem:     computedNetworkSecurityRuleSchema(),
+			},
+
+			"default_security_rule": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     computedNetworkSecurityRuleSchema(),
 			},
 
 			"tags": tagsForDataSourceSchema(),
@@ -92,7 +46,7 @@ func dataSourceArmNetworkSecurityGroupRead(d *schema.ResourceData, meta interfac
 	resp, err := client.Get(resourceGroup, name, "")
 	if err != nil {
 		if utils.ResponseWasNotFound(resp.Response) {
-			d.SetId("")
+			return fmt.Errorf("Error: Network Security Group %q (Resource Group %q) was not found", name, resourceGroup)
 		}
 		return fmt.Errorf("Error making Read request on Network Security Group %q (Resource Group %q): %+v", name, resourceGroup, err)
 	}
@@ -101,10 +55,17 @@ func dataSourceArmNetworkSecurityGroupRead(d *schema.ResourceData, meta interfac
 
 	d.Set("name", resp.Name)
 	d.Set("resource_group_name", resourceGroup)
-	d.Set("location", azureRMNormalizeLocation(*resp.Location))
+	if location := resp.Location; location != nil {
+		d.Set("location", azureRMNormalizeLocation(*location))
+	}
 
 	if props := resp.SecurityGroupPropertiesFormat; props != nil {
-		d.Set("security_rule", flattenNetworkSecurityRules(props.SecurityRules))
+		d.Set("security_rule", flattenNetworkSecurityRules(props.SecurityRules, ""))
+
+		// flattened in the same way as the Security Rules, so the effective rule set can be processed uniformly
+		if err := d.Set("default_security_rule", flattenNetworkSecurityRules(props.DefaultSecurityRules, "")); err != nil {
+			return fmt.Errorf("Error setting `default_security_rule`: %+v", err)
+		}
 	}
 
 	flattenAndSetTags(d, resp.Tags)
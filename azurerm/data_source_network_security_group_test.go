@@ -2,6 +2,7 @@ package azurerm
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/acctest"
@@ -24,6 +25,7 @@ func TestAccDataSourceAzureRMNetworkSecurityGroup_basic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(dataSourceName, "location"),
 					resource.TestCheckResourceAttr(dataSourceName, "security_rule.#", "0"),
+					resource.TestCheckResourceAttr(dataSourceName, "default_security_rule.#", "6"),
 					resource.TestCheckResourceAttr(dataSourceName, "tags.%", "0"),
 				),
 			},
@@ -56,6 +58,7 @@ func TestAccDataSourceAzureRMNetworkSecurityGroup_rules(t *testing.T) {
 					resource.TestCheckResourceAttr(dataSourceName, "security_rule.0.destination_port_range", "*"),
 					resource.TestCheckResourceAttr(dataSourceName, "security_rule.0.source_address_prefix", "*"),
 					resource.TestCheckResourceAttr(dataSourceName, "security_rule.0.destination_address_prefix", "*"),
+					resource.TestCheckResourceAttr(dataSourceName, "default_security_rule.#", "6"),
 					resource.TestCheckResourceAttr(dataSourceName, "tags.%", "0"),
 				),
 			},
@@ -87,6 +90,23 @@ func TestAccDataSourceAzureRMNetworkSecurityGroup_tags(t *testing.T) {
 	})
 }
 
+func TestAccDataSourceAzureRMNetworkSecurityGroup_notFound(t *testing.T) {
+	ri := acctest.RandInt()
+	location := testLocation()
+	config := testAccDataSourceAzureRMNetworkSecurityGroupNotFound(ri, location)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile("was not found"),
+			},
+		},
+	})
+}
+
 func testAccDataSourceAzureRMNetworkSecurityGroupBasic(rInt int, location string) string {
 	return fmt.Sprintf(`
 resource "azurerm_resource_group" "test" {
@@ -162,3 +182,17 @@ data "azurerm_network_security_group" "test" {
 }
 `, rInt, location, rInt)
 }
+
+func testAccDataSourceAzureRMNetworkSecurityGroupNotFound(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+data "azurerm_network_security_group" "test" {
+  name                = "acctestnsg-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+}
+`, rInt, location, rInt)
+}
@@ -8,10 +8,36 @@ func WasConflict(resp *http.Response) bool {
 	return responseWasStatusCode(resp, http.StatusConflict)
 }
 
+func WasForbidden(resp *http.Response) bool {
+	return responseWasStatusCode(resp, http.StatusForbidden)
+}
+
 func WasNotFound(resp *http.Response) bool {
 	return responseWasStatusCode(resp, http.StatusNotFound)
 }
 
+// WasThrottled returns whether the request was throttled, which Azure indicates with either
+// a 429 or a 503 depending on the Resource Provider
+func WasThrottled(resp *http.Response) bool {
+	return responseWasStatusCode(resp, http.StatusTooManyRequests) || responseWasStatusCode(resp, http.StatusServiceUnavailable)
+}
+
+// WasTooManyRequests returns whether the request was rejected with a 429, unlike WasThrottled
+// which also includes a 503 (which some Resource Providers also return when they're unavailable)
+func WasTooManyRequests(resp *http.Response) bool {
+	return responseWasStatusCode(resp, http.StatusTooManyRequests)
+}
+
+// RequestID returns the `x-ms-request-id` header of the response, which Azure Support
+// use to identify a request - or an empty string if there was no response
+func RequestID(resp *http.Response) string {
+	if resp == nil {
+		return ""
+	}
+
+	return resp.Header.Get("x-ms-request-id")
+}
+
 func responseWasStatusCode(resp *http.Response, statusCode int) bool {
 	if r := resp; r != nil {
 		if r.StatusCode == statusCode {
@@ -63,3 +63,114 @@ func TestNotFound_StatusCodes(t *testing.T) {
 		}
 	}
 }
+
+func TestThrottled_DroppedConnection(t *testing.T) {
+	resp := http.Response{}
+	if WasThrottled(&resp) {
+		t.Fatalf("wasThrottled should return `false` for a dropped connection")
+	}
+}
+
+func TestThrottled_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		statusCode     int
+		expectedResult bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusConflict, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusServiceUnavailable, true},
+	}
+
+	for _, test := range testCases {
+		resp := http.Response{
+			StatusCode: test.statusCode,
+		}
+		result := WasThrottled(&resp)
+		if test.expectedResult != result {
+			t.Fatalf("Expected '%+v' for status code '%d' - got '%+v'",
+				test.expectedResult, test.statusCode, result)
+		}
+	}
+}
+
+func TestTooManyRequests_DroppedConnection(t *testing.T) {
+	resp := http.Response{}
+	if WasTooManyRequests(&resp) {
+		t.Fatalf("wasTooManyRequests should return `false` for a dropped connection")
+	}
+}
+
+func TestTooManyRequests_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		statusCode     int
+		expectedResult bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusInternalServerError, false},
+		{http.StatusConflict, false},
+		{http.StatusServiceUnavailable, false},
+		{http.StatusTooManyRequests, true},
+	}
+
+	for _, test := range testCases {
+		resp := http.Response{
+			StatusCode: test.statusCode,
+		}
+		result := WasTooManyRequests(&resp)
+		if test.expectedResult != result {
+			t.Fatalf("Expected '%+v' for status code '%d' - got '%+v'",
+				test.expectedResult, test.statusCode, result)
+		}
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	testCases := []struct {
+		resp     *http.Response
+		expected string
+	}{
+		{nil, ""},
+		{&http.Response{}, ""},
+		{&http.Response{Header: http.Header{}}, ""},
+		{&http.Response{Header: http.Header{"X-Ms-Request-Id": []string{"abc-123"}}}, "abc-123"},
+	}
+
+	for _, test := range testCases {
+		result := RequestID(test.resp)
+		if test.expected != result {
+			t.Fatalf("Expected %q for the Request ID - got %q", test.expected, result)
+		}
+	}
+}
+
+func TestForbidden_DroppedConnection(t *testing.T) {
+	resp := http.Response{}
+	if WasForbidden(&resp) {
+		t.Fatalf("wasForbidden should return `false` for a dropped connection")
+	}
+}
+
+func TestForbidden_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		statusCode     int
+		expectedResult bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusForbidden, true},
+	}
+
+	for _, test := range testCases {
+		resp := http.Response{
+			StatusCode: test.statusCode,
+		}
+		result := WasForbidden(&resp)
+		if test.expectedResult != result {
+			t.Fatalf("Expected '%+v' for status code '%d' - got '%+v'",
+				test.expectedResult, test.statusCode, result)
+		}
+	}
+}
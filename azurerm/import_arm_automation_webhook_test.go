@@ -0,0 +1,36 @@
+package azurerm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccAzureRMAutomationWebhook_importBasic(t *testing.T) {
+	resourceName := "azurerm_automation_webhook.test"
+
+	ri := acctest.RandInt()
+	expiryTime := time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+	config := testAccAzureRMAutomationWebhook_basic(ri, testLocation(), expiryTime)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationWebhookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the URI is only returned when the Webhook is created
+				ImportStateVerifyIgnore: []string{"uri"},
+			},
+		},
+	})
+}
@@ -108,6 +108,30 @@ func TestAccAzureRMContainerRegistry_importBasicManagedPremium(t *testing.T) {
 	})
 }
 
+func TestAccAzureRMContainerRegistry_importGeoReplication(t *testing.T) {
+	resourceName := "azurerm_container_registry.test"
+
+	ri := acctest.RandInt()
+	config := testAccAzureRMContainerRegistry_geoReplication(ri, testLocation(), testAltLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMContainerRegistryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func TestAccAzureRMContainerRegistry_importComplete(t *testing.T) {
 	resourceName := "azurerm_container_registry.test"
 
@@ -69,3 +69,29 @@ func TestAccAzureRMNetworkSecurityGroup_importMultipleRules(t *testing.T) {
 		},
 	})
 }
+
+func TestAccAzureRMNetworkSecurityGroup_importNameCasing(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_named(rInt, testLocation(), "MyNSG"),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				Config:             testAccAzureRMNetworkSecurityGroup_named(rInt, testLocation(), "mynsg"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
@@ -1,9 +1,13 @@
 package azurerm
 
-// handle the case of using the same name for different kinds of resources
+// azureRMLockKey returns the key used to lock a resource, in the format `{resourceType}.{name}`
+// to handle the case of using the same name for different kinds of resources
+func azureRMLockKey(name string, resourceType string) string {
+	return resourceType + "." + name
+}
+
 func azureRMLockByName(name string, resourceType string) {
-	updatedName := resourceType + "." + name
-	armMutexKV.Lock(updatedName)
+	armMutexKV.Lock(azureRMLockKey(name, resourceType))
 }
 
 func azureRMLockMultipleByName(names *[]string, resourceType string) {
@@ -13,8 +17,7 @@ func azureRMLockMultipleByName(names *[]string, resourceType string) {
 }
 
 func azureRMUnlockByName(name string, resourceType string) {
-	updatedName := resourceType + "." + name
-	armMutexKV.Unlock(updatedName)
+	armMutexKV.Unlock(azureRMLockKey(name, resourceType))
 }
 
 func azureRMUnlockMultipleByName(names *[]string, resourceType string) {
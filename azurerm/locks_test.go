@@ -0,0 +1,40 @@
+package azurerm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAzureRMLockKey(t *testing.T) {
+	expected := "azurerm_network_security_group.example"
+	if actual := azureRMLockKey("example", networkSecurityGroupResourceName); actual != expected {
+		t.Fatalf("Expected the lock key to be %q but got %q", expected, actual)
+	}
+}
+
+func TestAzureRMLockByName_serializes(t *testing.T) {
+	name := "acctest-serialize"
+
+	azureRMLockByName(name, networkSecurityGroupResourceName)
+
+	acquired := make(chan struct{})
+	go func() {
+		azureRMLockByName(name, networkSecurityGroupResourceName)
+		defer azureRMUnlockByName(name, networkSecurityGroupResourceName)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatalf("Expected the second lock on %q to block whilst the first is held", name)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	azureRMUnlockByName(name, networkSecurityGroupResourceName)
+
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the second lock on %q to be acquired once the first was released", name)
+	}
+}
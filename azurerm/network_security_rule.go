@@ -2,7 +2,12 @@ package azurerm
 
 import (
 	"fmt"
+	"net"
+	"regexp"
+	"strconv"
 	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
 )
 
 func validateNetworkSecurityRuleProtocol(v interface{}, k string) (ws []string, errors []error) {
@@ -18,3 +23,328 @@ func validateNetworkSecurityRuleProtocol(v interface{}, k string) (ws []string,
 	}
 	return
 }
+
+// validateNetworkSecurityRuleAddressPrefix accepts an IPv4 or IPv6 CIDR/IP Address, `*` or a Service Tag.
+// Since Azure periodically introduces new Service Tags, plausible values which aren't a known
+// Service Tag return a warning rather than an error.
+func validateNetworkSecurityRuleAddressPrefix(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if value == "*" {
+		return
+	}
+
+	if ip := net.ParseIP(value); ip != nil {
+		return
+	}
+
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return
+	}
+
+	// Azure rejects Resource IDs with an unhelpful error, so point users at the supported alternatives instead
+	if strings.HasPrefix(strings.ToLower(value), "/subscriptions/") {
+		applicationSecurityGroupsField := "source_application_security_group_ids"
+		if strings.Contains(k, "destination_") {
+			applicationSecurityGroupsField = "destination_application_security_group_ids"
+		}
+
+		if id, err := parseAzureResourceID(value); err == nil && id.Path["subnets"] != "" {
+			errors = append(errors, fmt.Errorf("%q must be a CIDR, an IP Address, `*` or a Service Tag, got the ID of the Subnet %q - use the Subnet's address prefix, or assign the Network Interfaces within the Subnet to an Application Security Group and use `%s`", k, id.Path["subnets"], applicationSecurityGroupsField))
+			return
+		}
+
+		errors = append(errors, fmt.Errorf("%q must be a CIDR, an IP Address, `*` or a Service Tag, got the Resource ID %q - to target resources use an Application Security Group with `%s`", k, value, applicationSecurityGroupsField))
+		return
+	}
+
+	serviceTags := map[string]bool{
+		"virtualnetwork":    true,
+		"azureloadbalancer": true,
+		"internet":          true,
+		"any":               true,
+	}
+	if serviceTags[strings.ToLower(value)] {
+		return
+	}
+
+	if regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9.]*$`).MatchString(value) {
+		ws = append(ws, fmt.Sprintf("%q (%q) is not a known Service Tag - known Service Tags are `VirtualNetwork`, `AzureLoadBalancer`, `Internet` and `Any`", k, value))
+		return
+	}
+
+	errors = append(errors, fmt.Errorf("%q must be a CIDR, an IP Address, `*` or a Service Tag, got %q", k, value))
+	return
+}
+
+// isNetworkSecurityRuleServiceTag returns whether the address prefix is a Service Tag (e.g. `VirtualNetwork`),
+// that is anything other than `*` which doesn't parse as an IP Address or a CIDR
+func isNetworkSecurityRuleServiceTag(value string) bool {
+	if value == "" || value == "*" {
+		return false
+	}
+
+	if ip := net.ParseIP(value); ip != nil {
+		return false
+	}
+
+	if _, _, err := net.ParseCIDR(value); err == nil {
+		return false
+	}
+
+	return true
+}
+
+// validatePortRangeOrStar accepts `*`, a single port (e.g. `80`) or a range of ports (e.g. `1024-2048`)
+func validatePortRangeOrStar(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if value == "*" {
+		return
+	}
+
+	parsePort := func(input string) (int, error) {
+		port, err := strconv.Atoi(input)
+		if err != nil || port < 0 || port > 65535 {
+			return 0, fmt.Errorf("%q must be `*`, a port between 0 and 65535 or a range of ports (e.g. `1024-2048`), got %q", k, value)
+		}
+		return port, nil
+	}
+
+	parts := strings.Split(value, "-")
+	if len(parts) > 2 {
+		errors = append(errors, fmt.Errorf("%q must be `*`, a port between 0 and 65535 or a range of ports (e.g. `1024-2048`), got %q", k, value))
+		return
+	}
+
+	low, err := parsePort(parts[0])
+	if err != nil {
+		errors = append(errors, err)
+		return
+	}
+
+	if len(parts) == 2 {
+		high, err := parsePort(parts[1])
+		if err != nil {
+			errors = append(errors, err)
+			return
+		}
+
+		if low > high {
+			errors = append(errors, fmt.Errorf("%q must have the lower port first in a range of ports, got %q", k, value))
+		}
+	}
+
+	return
+}
+
+// validatePortRangeListOrStar accepts the same values as validatePortRangeOrStar, or a comma-separated
+// list of ports and port ranges (e.g. `80,443,8000-8080`)
+func validatePortRangeListOrStar(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	if !strings.Contains(value, ",") {
+		return validatePortRangeOrStar(value, k)
+	}
+
+	for _, portRange := range strings.Split(value, ",") {
+		portRange = strings.TrimSpace(portRange)
+		if portRange == "*" {
+			errors = append(errors, fmt.Errorf("%q cannot contain `*` in a list of ports, got %q", k, value))
+			continue
+		}
+
+		w, e := validatePortRangeOrStar(portRange, k)
+		ws = append(ws, w...)
+		errors = append(errors, e...)
+	}
+
+	return
+}
+
+// splitPortRanges splits a comma-separated list of ports and port ranges, ignoring any whitespace
+func splitPortRanges(input string) []string {
+	output := make([]string, 0)
+	for _, v := range strings.Split(input, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			output = append(output, v)
+		}
+	}
+	return output
+}
+
+// expandNetworkSecurityRulePortRanges returns the values to send to Azure for a `source_port_range` or
+// `destination_port_range` - where a comma-separated list of ports is sent as the plural field
+func expandNetworkSecurityRulePortRanges(portRange string) (*string, *[]string) {
+	if strings.Contains(portRange, ",") {
+		portRanges := splitPortRanges(portRange)
+		return nil, &portRanges
+	}
+
+	return &portRange, nil
+}
+
+// flattenNetworkSecurityRulePortRanges returns the values to set for a `source_port_range` or `destination_port_range`
+// and its plural field - where a configured comma-separated list of ports is kept when Azure returns the same ports
+func flattenNetworkSecurityRulePortRanges(configured string, portRange *string, portRanges *[]string) (string, []string) {
+	var flattenedRange string
+	if portRange != nil {
+		flattenedRange = *portRange
+	}
+
+	flattenedRanges := make([]string, 0)
+	if portRanges != nil {
+		flattenedRanges = *portRanges
+	}
+
+	if strings.Contains(configured, ",") && sliceToSet(flattenedRanges).Equal(sliceToSet(splitPortRanges(configured))) {
+		return configured, make([]string, 0)
+	}
+
+	return flattenedRange, flattenedRanges
+}
+
+// overlappingAddressPrefixes returns each pair of address prefixes where one contains the other (including
+// duplicates). IP Addresses are treated as single-address CIDRs, and Service Tags and `*` are ignored.
+func overlappingAddressPrefixes(prefixes []string) [][]string {
+	type parsedPrefix struct {
+		value   string
+		network *net.IPNet
+	}
+
+	parsed := make([]parsedPrefix, 0)
+	for _, prefix := range prefixes {
+		cidr := prefix
+		if ip := net.ParseIP(prefix); ip != nil {
+			if ip.To4() != nil {
+				cidr = prefix + "/32"
+			} else {
+				cidr = prefix + "/128"
+			}
+		}
+
+		if _, network, err := net.ParseCIDR(cidr); err == nil {
+			parsed = append(parsed, parsedPrefix{value: prefix, network: network})
+		}
+	}
+
+	overlaps := make([][]string, 0)
+	for i := 0; i < len(parsed); i++ {
+		for j := i + 1; j < len(parsed); j++ {
+			first := parsed[i]
+			second := parsed[j]
+
+			// since CIDRs are aligned, two networks overlap only if one contains the other's base address
+			if first.network.Contains(second.network.IP) || second.network.Contains(first.network.IP) {
+				overlaps = append(overlaps, []string{first.value, second.value})
+			}
+		}
+	}
+
+	return overlaps
+}
+
+// computedNetworkSecurityRuleSchema returns the schema for a read-only Security Rule, in the same
+// shape as the `security_rule` block of the `azurerm_network_security_group` resource
+func computedNetworkSecurityRuleSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"protocol": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"source_port_range": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"source_port_ranges": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"destination_port_range": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"destination_port_ranges": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"source_address_prefix": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"source_address_prefix_is_service_tag": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"source_address_prefixes": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"destination_address_prefix": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"destination_address_prefixes": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"source_application_security_group_ids": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"destination_application_security_group_ids": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+
+			"access": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"priority": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"direction": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
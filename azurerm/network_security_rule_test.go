@@ -1,6 +1,12 @@
 package azurerm
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
 
 func TestResourceAzureRMNetworkSecurityRuleProtocol_validation(t *testing.T) {
 	cases := []struct {
@@ -41,3 +47,388 @@ func TestResourceAzureRMNetworkSecurityRuleProtocol_validation(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceAzureRMNetworkSecurityRuleAddressPrefix_validation(t *testing.T) {
+	cases := []struct {
+		Value     string
+		WarnCount int
+		ErrCount  int
+	}{
+		{
+			Value:    "*",
+			ErrCount: 0,
+		},
+		{
+			Value:    "10.0.0.1",
+			ErrCount: 0,
+		},
+		{
+			Value:    "10.0.0.0/16",
+			ErrCount: 0,
+		},
+		{
+			Value:    "VirtualNetwork",
+			ErrCount: 0,
+		},
+		{
+			Value:    "AzureLoadBalancer",
+			ErrCount: 0,
+		},
+		{
+			Value:    "internet",
+			ErrCount: 0,
+		},
+		{
+			Value:    "Any",
+			ErrCount: 0,
+		},
+		{
+			Value:     "Interet",
+			WarnCount: 1,
+			ErrCount:  0,
+		},
+		{
+			Value:     "Storage.WestUS",
+			WarnCount: 1,
+			ErrCount:  0,
+		},
+		{
+			Value:    "2001:db8::1",
+			ErrCount: 0,
+		},
+		{
+			Value:    "2001:DB8::/32",
+			ErrCount: 0,
+		},
+		{
+			Value:    "::/0",
+			ErrCount: 0,
+		},
+		{
+			Value:    "10.0.0.0/33",
+			ErrCount: 1,
+		},
+		{
+			Value:    "2001:db8::/129",
+			ErrCount: 1,
+		},
+		{
+			Value:    "2001:db8:::1",
+			ErrCount: 1,
+		},
+		{
+			Value:    "10.0.0.256",
+			ErrCount: 1,
+		},
+		{
+			Value:    "Virtual Network",
+			ErrCount: 1,
+		},
+		{
+			Value:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Network/virtualNetworks/network1/subnets/subnet1",
+			ErrCount: 1,
+		},
+		{
+			Value:    "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Network/applicationSecurityGroups/asg1",
+			ErrCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		warnings, errors := validateNetworkSecurityRuleAddressPrefix(tc.Value, "source_address_prefix")
+
+		if len(warnings) != tc.WarnCount {
+			t.Fatalf("Expected %q to trigger %d validation warnings but got %d", tc.Value, tc.WarnCount, len(warnings))
+		}
+
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected %q to trigger %d validation errors but got %d", tc.Value, tc.ErrCount, len(errors))
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRuleAddressPrefix_subnetID(t *testing.T) {
+	subnetID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Network/virtualNetworks/network1/subnets/subnet1"
+
+	cases := map[string]string{
+		"source_address_prefix":                              "source_application_security_group_ids",
+		"destination_address_prefix":                         "destination_application_security_group_ids",
+		"security_rule.1234.destination_address_prefixes.56": "destination_application_security_group_ids",
+	}
+
+	for key, expected := range cases {
+		_, errors := validateNetworkSecurityRuleAddressPrefix(subnetID, key)
+		if len(errors) != 1 {
+			t.Fatalf("Expected 1 validation error for %q but got %d", key, len(errors))
+		}
+
+		message := errors[0].Error()
+		if !strings.Contains(message, "subnet1") || !strings.Contains(message, expected) {
+			t.Fatalf("Expected the error for %q to name the Subnet and suggest %q: %s", key, expected, message)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRule_isServiceTag(t *testing.T) {
+	cases := []struct {
+		Value    string
+		Expected bool
+	}{
+		{Value: "", Expected: false},
+		{Value: "*", Expected: false},
+		{Value: "10.0.0.1", Expected: false},
+		{Value: "10.0.0.0/24", Expected: false},
+		{Value: "2001:db8::/32", Expected: false},
+		{Value: "fd00::1", Expected: false},
+		{Value: "VirtualNetwork", Expected: true},
+		{Value: "AzureLoadBalancer", Expected: true},
+		{Value: "Internet", Expected: true},
+		{Value: "Storage.WestEurope", Expected: true},
+	}
+
+	for _, tc := range cases {
+		if actual := isNetworkSecurityRuleServiceTag(tc.Value); actual != tc.Expected {
+			t.Fatalf("Expected %q to return %t but got %t", tc.Value, tc.Expected, actual)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRulePortRange_validation(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{
+			Value:    "*",
+			ErrCount: 0,
+		},
+		{
+			Value:    "0",
+			ErrCount: 0,
+		},
+		{
+			Value:    "80",
+			ErrCount: 0,
+		},
+		{
+			Value:    "65535",
+			ErrCount: 0,
+		},
+		{
+			Value:    "65536",
+			ErrCount: 1,
+		},
+		{
+			Value:    "-1",
+			ErrCount: 1,
+		},
+		{
+			Value:    "1024-2048",
+			ErrCount: 0,
+		},
+		{
+			Value:    "80-80",
+			ErrCount: 0,
+		},
+		{
+			Value:    "100-50",
+			ErrCount: 1,
+		},
+		{
+			Value:    "1-2-3",
+			ErrCount: 1,
+		},
+		{
+			Value:    "http",
+			ErrCount: 1,
+		},
+		{
+			Value:    "",
+			ErrCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		_, errors := validatePortRangeOrStar(tc.Value, "source_port_range")
+
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected validatePortRangeOrStar to trigger '%d' errors for '%s' - got '%d'", tc.ErrCount, tc.Value, len(errors))
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRulePortRangeList_validation(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{
+			Value:    "*",
+			ErrCount: 0,
+		},
+		{
+			Value:    "80",
+			ErrCount: 0,
+		},
+		{
+			Value:    "80,443",
+			ErrCount: 0,
+		},
+		{
+			Value:    "80, 443, 8000-8080",
+			ErrCount: 0,
+		},
+		{
+			Value:    "80,*",
+			ErrCount: 1,
+		},
+		{
+			Value:    "80,http,70000",
+			ErrCount: 2,
+		},
+		{
+			Value:    "80,,443",
+			ErrCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		_, errors := validatePortRangeListOrStar(tc.Value, "destination_port_range")
+
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected validatePortRangeListOrStar to trigger '%d' errors for '%s' - got '%d'", tc.ErrCount, tc.Value, len(errors))
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRule_splitPortRanges(t *testing.T) {
+	actual := splitPortRanges(" 80,443 ,,8000-8080")
+	expected := []string{"80", "443", "8000-8080"}
+
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %d port ranges but got %d: %+v", len(expected), len(actual), actual)
+	}
+
+	for i, v := range expected {
+		if actual[i] != v {
+			t.Fatalf("Expected port range %d to be %q but got %q", i, v, actual[i])
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRule_expandPortRanges(t *testing.T) {
+	portRange, portRanges := expandNetworkSecurityRulePortRanges("22")
+	if portRange == nil || *portRange != "22" || portRanges != nil {
+		t.Fatalf("Expected a single port to be sent as the port range but got %v / %v", portRange, portRanges)
+	}
+
+	portRange, portRanges = expandNetworkSecurityRulePortRanges("80, 443")
+	if portRange != nil || portRanges == nil || !reflect.DeepEqual(*portRanges, []string{"80", "443"}) {
+		t.Fatalf("Expected a comma-separated list of ports to be sent as the port ranges but got %v / %v", portRange, portRanges)
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRule_flattenPortRanges(t *testing.T) {
+	cases := []struct {
+		Name           string
+		Configured     string
+		PortRange      *string
+		PortRanges     *[]string
+		ExpectedRange  string
+		ExpectedRanges []string
+	}{
+		{
+			Name:           "Single Port",
+			Configured:     "22",
+			PortRange:      utils.String("22"),
+			ExpectedRange:  "22",
+			ExpectedRanges: []string{},
+		},
+		{
+			Name:           "Comma-Separated Ports",
+			Configured:     "80,443",
+			PortRange:      utils.String(""),
+			PortRanges:     &[]string{"443", "80"},
+			ExpectedRange:  "80,443",
+			ExpectedRanges: []string{},
+		},
+		{
+			Name:           "Comma-Separated Ports changed outside of Terraform",
+			Configured:     "80,443",
+			PortRange:      utils.String(""),
+			PortRanges:     &[]string{"80", "8080"},
+			ExpectedRange:  "",
+			ExpectedRanges: []string{"80", "8080"},
+		},
+		{
+			Name:           "Imported",
+			Configured:     "",
+			PortRanges:     &[]string{"80", "443"},
+			ExpectedRange:  "",
+			ExpectedRanges: []string{"80", "443"},
+		},
+	}
+
+	for _, tc := range cases {
+		portRange, portRanges := flattenNetworkSecurityRulePortRanges(tc.Configured, tc.PortRange, tc.PortRanges)
+		if portRange != tc.ExpectedRange || !reflect.DeepEqual(portRanges, tc.ExpectedRanges) {
+			t.Fatalf("Expected %q to be flattened to %q / %+v but got %q / %+v", tc.Name, tc.ExpectedRange, tc.ExpectedRanges, portRange, portRanges)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityRule_overlappingAddressPrefixes(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Prefixes []string
+		Overlaps int
+	}{
+		{
+			Name:     "Disjoint",
+			Prefixes: []string{"10.0.0.0/24", "10.0.1.0/24", "192.168.0.1"},
+			Overlaps: 0,
+		},
+		{
+			Name:     "Duplicates",
+			Prefixes: []string{"10.0.0.0/24", "10.0.0.0/24"},
+			Overlaps: 1,
+		},
+		{
+			Name:     "Contained CIDR",
+			Prefixes: []string{"10.0.0.0/16", "10.0.5.0/24"},
+			Overlaps: 1,
+		},
+		{
+			Name:     "Contained IP Address",
+			Prefixes: []string{"10.0.5.4", "10.0.0.0/16"},
+			Overlaps: 1,
+		},
+		{
+			Name:     "Multiple Overlaps",
+			Prefixes: []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.3"},
+			Overlaps: 3,
+		},
+		{
+			Name:     "IPv6",
+			Prefixes: []string{"2001:db8::/32", "2001:db8:1::/48", "2001:db9::/32"},
+			Overlaps: 1,
+		},
+		{
+			Name:     "IPv4 and IPv6",
+			Prefixes: []string{"0.0.0.0/0", "::/0"},
+			Overlaps: 0,
+		},
+		{
+			Name:     "Service Tags",
+			Prefixes: []string{"VirtualNetwork", "Internet", "*", "10.0.0.0/8"},
+			Overlaps: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		overlaps := overlappingAddressPrefixes(tc.Prefixes)
+		if len(overlaps) != tc.Overlaps {
+			t.Fatalf("Expected %q to have %d overlaps but got %d: %+v", tc.Name, tc.Overlaps, len(overlaps), overlaps)
+		}
+	}
+}
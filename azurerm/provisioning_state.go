@@ -0,0 +1,43 @@
+package azurerm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// provisioningStateGetter returns the current Provisioning State of a resource
+type provisioningStateGetter func() (string, error)
+
+// provisioningStateRefreshFunc wraps a provisioningStateGetter so it can be used to poll
+// for a resource to finish provisioning. A `Failed` state is surfaced as an error
+// rather than being polled until the timeout is reached.
+func provisioningStateRefreshFunc(getter provisioningStateGetter) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		state, err := getter()
+		if err != nil {
+			return nil, "", err
+		}
+
+		if strings.EqualFold(state, "Failed") {
+			return nil, "", fmt.Errorf("the resource entered the %q Provisioning State", state)
+		}
+
+		// WaitForState treats a nil result as the resource not existing, so return the state instead
+		return state, state, nil
+	}
+}
+
+// provisioningStateChangeConf returns a StateChangeConf which waits for the resource to
+// transition from `Creating`/`Updating` to `Succeeded` within the given timeout.
+func provisioningStateChangeConf(getter provisioningStateGetter, timeout time.Duration) *resource.StateChangeConf {
+	return &resource.StateChangeConf{
+		Pending:    []string{"Creating", "Updating"},
+		Target:     []string{"Succeeded"},
+		Refresh:    provisioningStateRefreshFunc(getter),
+		Timeout:    timeout,
+		MinTimeout: 15 * time.Second,
+	}
+}
@@ -0,0 +1,104 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestProvisioningStateRefreshFunc(t *testing.T) {
+	cases := []struct {
+		Name          string
+		State         string
+		Error         error
+		ExpectedState string
+		ExpectError   bool
+	}{
+		{
+			Name:          "Succeeded",
+			State:         "Succeeded",
+			ExpectedState: "Succeeded",
+		},
+		{
+			Name:          "Updating",
+			State:         "Updating",
+			ExpectedState: "Updating",
+		},
+		{
+			Name:        "Failed",
+			State:       "Failed",
+			ExpectError: true,
+		},
+		{
+			Name:        "Getter Error",
+			Error:       fmt.Errorf("Error retrieving resource"),
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		getter := func() (string, error) {
+			return tc.State, tc.Error
+		}
+
+		result, state, err := provisioningStateRefreshFunc(getter)()
+		if tc.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected %q to return an error but didn't get one", tc.Name)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected %q not to return an error but got: %+v", tc.Name, err)
+		}
+
+		if result == nil {
+			t.Fatalf("Expected %q to return a non-nil result", tc.Name)
+		}
+
+		if state != tc.ExpectedState {
+			t.Fatalf("Expected %q to return the state %q but got %q", tc.Name, tc.ExpectedState, state)
+		}
+	}
+}
+
+func TestProvisioningStateChangeConf_waitsForSucceeded(t *testing.T) {
+	states := []string{"Creating", "Updating", "Succeeded"}
+	calls := 0
+	getter := func() (string, error) {
+		state := states[calls]
+		if calls < len(states)-1 {
+			calls++
+		}
+		return state, nil
+	}
+
+	stateConf := provisioningStateChangeConf(getter, time.Minute)
+	stateConf.MinTimeout = 0
+	stateConf.Delay = 0
+	stateConf.PollInterval = time.Millisecond
+
+	result, err := stateConf.WaitForState()
+	if err != nil {
+		t.Fatalf("Expected the state change to succeed but got: %+v", err)
+	}
+
+	if result.(string) != "Succeeded" {
+		t.Fatalf("Expected the final state to be `Succeeded` but got %q", result)
+	}
+}
+
+func TestProvisioningStateChangeConf_failed(t *testing.T) {
+	getter := func() (string, error) {
+		return "Failed", nil
+	}
+
+	stateConf := provisioningStateChangeConf(getter, time.Minute)
+	stateConf.MinTimeout = 0
+	stateConf.PollInterval = time.Millisecond
+
+	if _, err := stateConf.WaitForState(); err == nil {
+		t.Fatalf("Expected the state change to fail but it didn't")
+	}
+}
@@ -1,8 +1,13 @@
 package azurerm
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/arm/automation"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -12,9 +17,9 @@ import (
 
 func resourceArmAutomationAccount() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceArmAutomationAccountCreateUpdate,
+		Create: resourceArmAutomationAccountCreate,
 		Read:   resourceArmAutomationAccountRead,
-		Update: resourceArmAutomationAccountCreateUpdate,
+		Update: resourceArmAutomationAccountUpdate,
 		Delete: resourceArmAutomationAccountDelete,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -22,9 +27,10 @@ func resourceArmAutomationAccount() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateAutomationAccountName,
 			},
 
 			"location": locationSchema(),
@@ -32,9 +38,12 @@ func resourceArmAutomationAccount() *schema.Resource {
 			"resource_group_name": resourceGroupNameSchema(),
 
 			"sku": {
-				Type:     schema.TypeList,
-				Required: true,
-				MaxItems: 1,
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				MaxItems:      1,
+				Deprecated:    "`sku` has been replaced by `sku_name`.",
+				ConflictsWith: []string{"sku_name"},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
@@ -44,27 +53,70 @@ func resourceArmAutomationAccount() *schema.Resource {
 							DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
 							ValidateFunc: validation.StringInSlice([]string{
 								string(automation.Basic),
+								string(automation.Free),
 							}, true),
 						},
 					},
 				},
 			},
 
+			"sku_name": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ConflictsWith:    []string{"sku"},
+				DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(automation.Basic),
+					string(automation.Free),
+				}, true),
+			},
+
 			"tags": tagsSchema(),
+
+			"creation_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"last_modified_time": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"dsc_server_endpoint": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"dsc_primary_access_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"dsc_secondary_access_key": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
 		},
 	}
 }
 
-func resourceArmAutomationAccountCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceArmAutomationAccountCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*ArmClient).automationAccountClient
 	log.Printf("[INFO] preparing arguments for AzureRM Automation Account creation.")
 
 	name := d.Get("name").(string)
 	location := d.Get("location").(string)
 	resGroup := d.Get("resource_group_name").(string)
-	tags := d.Get("tags").(map[string]interface{})
+	tags := mergeDefaultTags(meta.(*ArmClient).defaultTags, d.Get("tags").(map[string]interface{}))
 
-	sku := expandSku(d)
+	sku, err := expandAutomationAccountSku(d)
+	if err != nil {
+		return err
+	}
 
 	parameters := automation.AccountCreateOrUpdateParameters{
 		AccountCreateOrUpdateProperties: &automation.AccountCreateOrUpdateProperties{
@@ -75,8 +127,12 @@ func resourceArmAutomationAccountCreateUpdate(d *schema.ResourceData, meta inter
 		Tags:     expandTags(tags),
 	}
 
-	_, err := client.CreateOrUpdate(resGroup, name, parameters)
+	_, err = client.CreateOrUpdate(resGroup, name, parameters)
 	if err != nil {
+		if wasResourceGroupNotFound(err) {
+			return resourceGroupNotFoundError(resGroup, err)
+		}
+
 		return err
 	}
 
@@ -94,6 +150,51 @@ func resourceArmAutomationAccountCreateUpdate(d *schema.ResourceData, meta inter
 	return resourceArmAutomationAccountRead(d, meta)
 }
 
+func resourceArmAutomationAccountUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationAccountClient
+	log.Printf("[INFO] preparing arguments for AzureRM Automation Account update.")
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+
+	// only the fields which have changed are sent, so that (for example) a change to the tags
+	// doesn't overwrite a SKU which has been changed outside of Terraform
+	parameters := automation.AccountUpdateParameters{
+		AccountUpdateProperties: &automation.AccountUpdateProperties{},
+	}
+
+	if d.HasChange("sku") || d.HasChange("sku_name") {
+		sku, err := expandAutomationAccountSku(d)
+		if err != nil {
+			return err
+		}
+		parameters.AccountUpdateProperties.Sku = &sku
+	}
+
+	// the tags are always sent (rather than only when `tags` changes) so that changes to the provider's `default_tags`
+	// are applied - and since they're replaced as a whole, any hidden tags added by Azure need to be sent back
+	existing, err := client.Get(resGroup, name)
+	if err != nil {
+		return fmt.Errorf("Error retrieving Automation Account %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	tags := mergeDefaultTags(meta.(*ArmClient).defaultTags, d.Get("tags").(map[string]interface{}))
+	parameters.Tags = expandTags(mergeHiddenTags(tags, existing.Tags, meta.(*ArmClient).hiddenTagPrefix))
+
+	read, err := client.Update(resGroup, name, parameters)
+	if err != nil {
+		return fmt.Errorf("Error updating Automation Account %q (Resource Group %q): %+v", name, resGroup, err)
+	}
+
+	if sku := parameters.AccountUpdateProperties.Sku; sku != nil && read.AccountProperties != nil && read.Sku != nil {
+		if !strings.EqualFold(string(sku.Name), string(read.Sku.Name)) {
+			log.Printf("[WARN] Automation Account %q (Resource Group %q) was updated to the SKU %q but the API returned %q", name, resGroup, string(sku.Name), string(read.Sku.Name))
+		}
+	}
+
+	return resourceArmAutomationAccountRead(d, meta)
+}
+
 func resourceArmAutomationAccountRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*ArmClient).automationAccountClient
 	id, err := parseAzureResourceID(d.Id())
@@ -116,13 +217,82 @@ func resourceArmAutomationAccountRead(d *schema.ResourceData, meta interface{})
 	d.Set("name", resp.Name)
 	d.Set("location", azureRMNormalizeLocation(*resp.Location))
 	d.Set("resource_group_name", resGroup)
-	flattenAndSetSku(d, resp.Sku)
 
-	flattenAndSetTags(d, resp.Tags)
+	creationTime := ""
+	lastModifiedTime := ""
+	if props := resp.AccountProperties; props != nil {
+		// the SKU is always set from the API, so that a change made outside of Terraform is
+		// detected - or, when it's in `ignore_changes`, reflected in the state without being reverted
+		flattenAndSetSku(d, props.Sku)
+
+		if sku := props.Sku; sku != nil {
+			// as with the `sku` block, keep the configured casing when it matches
+			skuName := string(sku.Name)
+			if v, ok := d.GetOk("sku_name"); ok && strings.EqualFold(v.(string), skuName) {
+				skuName = v.(string)
+			}
+			d.Set("sku_name", skuName)
+		}
+
+		if v := props.CreationTime; v != nil {
+			creationTime = v.Format(time.RFC3339)
+		}
+		if v := props.LastModifiedTime; v != nil {
+			lastModifiedTime = v.Format(time.RFC3339)
+		}
+	}
+	d.Set("creation_time", creationTime)
+	d.Set("last_modified_time", lastModifiedTime)
+
+	flattenAndSetTagsWithDefaults(d, resp.Tags, meta.(*ArmClient).defaultTags, meta.(*ArmClient).hiddenTagPrefix)
+
+	// the registration info is only used for onboarding DSC nodes, so it's not worth failing the read over
+	registrationClient := meta.(*ArmClient).automationAgentRegistrationClient
+	ctx, cancel := context.WithTimeout(meta.(*ArmClient).StopContext, d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	var registration automation.AgentRegistration
+	err = retryOn429(ctx, func() (*http.Response, error) {
+		var err error
+		registration, err = registrationClient.Get(resGroup, name)
+		return registration.Response.Response, err
+	})
+	if err != nil {
+		log.Printf("[WARN] Unable to retrieve the Agent Registration Information for AzureRM Automation Account %q (Resource Group %q): %+v", name, resGroup, err)
+		d.Set("dsc_server_endpoint", "")
+		d.Set("dsc_primary_access_key", "")
+		d.Set("dsc_secondary_access_key", "")
+		return nil
+	}
+
+	flattenAndSetAutomationAccountAgentRegistration(d, registration)
 
 	return nil
 }
 
+func flattenAndSetAutomationAccountAgentRegistration(d *schema.ResourceData, registration automation.AgentRegistration) {
+	// the domain of the DSC Server Endpoint differs between clouds (such as US Government and China),
+	// so it's always taken from the API rather than being built from the Account name
+	endpoint := ""
+	if v := registration.Endpoint; v != nil {
+		endpoint = *v
+	}
+	d.Set("dsc_server_endpoint", endpoint)
+
+	primaryKey := ""
+	secondaryKey := ""
+	if keys := registration.Keys; keys != nil {
+		if v := keys.Primary; v != nil {
+			primaryKey = *v
+		}
+		if v := keys.Secondary; v != nil {
+			secondaryKey = *v
+		}
+	}
+	d.Set("dsc_primary_access_key", primaryKey)
+	d.Set("dsc_secondary_access_key", secondaryKey)
+}
+
 func resourceArmAutomationAccountDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*ArmClient).automationAccountClient
 
@@ -147,17 +317,42 @@ func resourceArmAutomationAccountDelete(d *schema.ResourceData, meta interface{}
 }
 
 func flattenAndSetSku(d *schema.ResourceData, sku *automation.Sku) {
+	if sku == nil {
+		return
+	}
+
+	// the API may return a different casing to the one which was configured - since the
+	// comparison is case-insensitive we keep the existing value to avoid a spurious diff
+	name := string(sku.Name)
+	if v, ok := d.GetOk("sku.0.name"); ok && strings.EqualFold(v.(string), name) {
+		name = v.(string)
+	}
+
 	results := make([]interface{}, 1)
 
 	result := map[string]interface{}{}
-	result["name"] = string(sku.Name)
+	result["name"] = name
 	results[0] = result
 
 	d.Set("sku", &results)
 }
 
-func expandSku(d *schema.ResourceData) automation.Sku {
+// expandAutomationAccountSku returns the Sku from either `sku_name` or the deprecated `sku` block.
+// Since both are Computed (and populated from the API), the one which has changed takes precedence.
+func expandAutomationAccountSku(d *schema.ResourceData) (automation.Sku, error) {
+	if d.HasChange("sku_name") || !d.HasChange("sku") {
+		if v, ok := d.GetOk("sku_name"); ok {
+			return automation.Sku{
+				Name: automation.SkuNameEnum(v.(string)),
+			}, nil
+		}
+	}
+
 	inputs := d.Get("sku").([]interface{})
+	if len(inputs) == 0 || inputs[0] == nil {
+		return automation.Sku{}, fmt.Errorf("One of `sku_name` or `sku` must be specified")
+	}
+
 	input := inputs[0].(map[string]interface{})
 	name := automation.SkuNameEnum(input["name"].(string))
 
@@ -165,5 +360,27 @@ func expandSku(d *schema.ResourceData) automation.Sku {
 		Name: name,
 	}
 
-	return sku
+	return sku, nil
+}
+
+func validateAutomationAccountName(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9-]*$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"%q must start with a letter and can only contain alphanumeric characters and hyphens: %q", k, value))
+	}
+
+	if strings.HasSuffix(value, "-") {
+		errors = append(errors, fmt.Errorf("%q cannot end with a hyphen: %q", k, value))
+	}
+
+	if len(value) < 6 {
+		errors = append(errors, fmt.Errorf("%q cannot be less than 6 characters: %q", k, value))
+	}
+
+	if len(value) > 50 {
+		errors = append(errors, fmt.Errorf("%q cannot be longer than 50 characters: %q", k, value))
+	}
+
+	return
 }
@@ -1,15 +1,125 @@
 package azurerm
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
 )
 
+func TestAccAzureRMAutomationAccountName_validation(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{
+			Value:    "short",
+			ErrCount: 1,
+		},
+		{
+			Value:    "sixsix",
+			ErrCount: 0,
+		},
+		{
+			Value:    "hello-world",
+			ErrCount: 0,
+		},
+		{
+			Value:    "hello-world-",
+			ErrCount: 1,
+		},
+		{
+			Value:    "1helloworld",
+			ErrCount: 1,
+		},
+		{
+			Value:    "hello_world",
+			ErrCount: 1,
+		},
+		{
+			Value:    "hello@world",
+			ErrCount: 1,
+		},
+		{
+			Value:    "helloWorld12",
+			ErrCount: 0,
+		},
+		{
+			Value:    strings.Repeat("a", 50),
+			ErrCount: 0,
+		},
+		{
+			Value:    strings.Repeat("a", 51),
+			ErrCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateAutomationAccountName(tc.Value, "azurerm_automation_account")
+
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected the Azure RM Automation Account Name to trigger a validation error for '%s'", tc.Value)
+		}
+	}
+}
+
+func TestAzureRMAutomationAccount_clientEndpoints(t *testing.T) {
+	environments := []azure.Environment{
+		azure.PublicCloud,
+		azure.USGovernmentCloud,
+		azure.ChinaCloud,
+		azure.GermanCloud,
+		{
+			Name:                    "FakeCloud",
+			ResourceManagerEndpoint: "https://management.fakecloud.example/",
+		},
+	}
+
+	for _, env := range environments {
+		client := &ArmClient{}
+		client.registerAutomationClients(env.ResourceManagerEndpoint, "00000000-0000-0000-0000-000000000000", autorest.NullAuthorizer{}, nil)
+
+		if uri := client.automationAccountClient.BaseURI; uri != env.ResourceManagerEndpoint {
+			t.Fatalf("Expected the Automation Account client for %q to use %q but got %q", env.Name, env.ResourceManagerEndpoint, uri)
+		}
+
+		if uri := client.automationAgentRegistrationClient.BaseURI; uri != env.ResourceManagerEndpoint {
+			t.Fatalf("Expected the Agent Registration client for %q to use %q but got %q", env.Name, env.ResourceManagerEndpoint, uri)
+		}
+	}
+}
+
+func TestAzureRMAutomationAccount_flattenAgentRegistrationEndpoint(t *testing.T) {
+	endpoints := []string{
+		"https://we-agentservice-prod-1.azure-automation.net/accounts/00000000-0000-0000-0000-000000000000",
+		"https://usge-agentservice-prod-1.azure-automation.us/accounts/00000000-0000-0000-0000-000000000000",
+		"https://sha2-agentservice-prod-1.azure-automation.cn/accounts/00000000-0000-0000-0000-000000000000",
+	}
+
+	for _, endpoint := range endpoints {
+		d := schema.TestResourceDataRaw(t, resourceArmAutomationAccount().Schema, map[string]interface{}{})
+		flattenAndSetAutomationAccountAgentRegistration(d, automation.AgentRegistration{
+			Endpoint: utils.String(endpoint),
+		})
+
+		if actual := d.Get("dsc_server_endpoint").(string); actual != endpoint {
+			t.Fatalf("Expected the DSC Server Endpoint to be %q but got %q", endpoint, actual)
+		}
+	}
+}
+
 func TestAccAzureRMAutomationAccount_basic(t *testing.T) {
 	ri := acctest.RandInt()
 	resourceName := "azurerm_automation_account.test"
@@ -25,6 +135,11 @@ func TestAccAzureRMAutomationAccount_basic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testCheckAzureRMAutomationAccountExists(resourceName),
 					resource.TestCheckResourceAttr(resourceName, "sku.0.name", "Basic"),
+					resource.TestCheckResourceAttrSet(resourceName, "creation_time"),
+					resource.TestCheckResourceAttrSet(resourceName, "last_modified_time"),
+					resource.TestCheckResourceAttrSet(resourceName, "dsc_server_endpoint"),
+					resource.TestCheckResourceAttrSet(resourceName, "dsc_primary_access_key"),
+					resource.TestCheckResourceAttrSet(resourceName, "dsc_secondary_access_key"),
 				),
 			},
 		},
@@ -53,6 +168,243 @@ func TestAccAzureRMAutomationAccount_complete(t *testing.T) {
 	})
 }
 
+func TestAccAzureRMAutomationAccount_updateTags(t *testing.T) {
+	ri := acctest.RandInt()
+	resourceName := "azurerm_automation_account.test"
+	config := testAccAzureRMAutomationAccount_basic(ri, testLocation())
+	updatedConfig := testAccAzureRMAutomationAccount_complete(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "0"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sku.0.name", "Basic"),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags.hello", "world"),
+				),
+			},
+		},
+	})
+}
+
+func TestAzureRMAutomationAccount_updateDoesNotResendUnchangedSku(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.Automation/automationAccounts/acctest"
+
+	// the SKU was changed to Free outside of Terraform, and `sku_name` is in `ignore_changes` - so
+	// Terraform Core leaves it out of the diff, and only the tags have changed
+	state := &terraform.InstanceState{
+		ID: id,
+		Attributes: map[string]string{
+			"id":                  id,
+			"name":                "acctest",
+			"location":            "westeurope",
+			"resource_group_name": "acctestRG",
+			"sku_name":            "Basic",
+			"tags.%":              "1",
+			"tags.environment":    "Production",
+		},
+	}
+	rawConfig, err := config.NewRawConfig(map[string]interface{}{
+		"name":                "acctest",
+		"location":            "westeurope",
+		"resource_group_name": "acctestRG",
+		"sku_name":            "Basic",
+		"tags": map[string]interface{}{
+			"environment": "Staging",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error building the configuration: %+v", err)
+	}
+
+	patches := make([]map[string]interface{}, 0)
+	sender := autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		if r.Method == http.MethodPatch {
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				return nil, err
+			}
+			patches = append(patches, body)
+		}
+
+		body := fmt.Sprintf(`{"id": %q, "name": "acctest", "location": "westeurope", "properties": {"sku": {"name": "Free"}}, "tags": {"environment": "Staging"}}`, id)
+		if strings.Contains(r.URL.Path, "agentRegistrationInformation") {
+			body = `{"endpoint": "https://we-agentservice-prod-1.azure-automation.net/accounts/00000000-0000-0000-0000-000000000000"}`
+		}
+
+		return testAzureResponse(r, http.StatusOK, body), nil
+	})
+
+	client := &ArmClient{StopContext: context.Background()}
+	client.registerAutomationClients("https://management.azure.com", "00000000-0000-0000-0000-000000000000", autorest.NullAuthorizer{}, sender)
+
+	r := resourceArmAutomationAccount()
+	diff, err := r.Diff(state, terraform.NewResourceConfig(rawConfig))
+	if err != nil {
+		t.Fatalf("Error diffing: %+v", err)
+	}
+
+	newState, err := r.Apply(state, diff, client)
+	if err != nil {
+		t.Fatalf("Error applying: %+v", err)
+	}
+
+	if len(patches) != 1 {
+		t.Fatalf("Expected a single PATCH request but got %d", len(patches))
+	}
+
+	if _, ok := patches[0]["tags"]; !ok {
+		t.Fatalf("Expected the changed tags to be sent but got %+v", patches[0])
+	}
+
+	if properties, ok := patches[0]["properties"].(map[string]interface{}); ok {
+		if sku, ok := properties["sku"]; ok {
+			t.Fatalf("Expected the unchanged SKU not to be sent but got %+v", sku)
+		}
+	}
+
+	if v := newState.Attributes["sku_name"]; v != "Free" {
+		t.Fatalf("Expected the SKU to be read from the API as %q but got %q", "Free", v)
+	}
+}
+
+func TestAccAzureRMAutomationAccount_ignoreSkuChanges(t *testing.T) {
+	ri := acctest.RandInt()
+	resourceName := "azurerm_automation_account.test"
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMAutomationAccount_ignoreSkuChanges(ri, location, "Production"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sku_name", "Basic"),
+					testCheckAzureRMAutomationAccountUpdateSku(resourceName, automation.Free),
+				),
+			},
+			{
+				// changing the tags mustn't revert the SKU which was changed outside of Terraform
+				Config: testAccAzureRMAutomationAccount_ignoreSkuChanges(ri, location, "Staging"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sku_name", "Free"),
+					resource.TestCheckResourceAttr(resourceName, "tags.environment", "Staging"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationAccount_free(t *testing.T) {
+	ri := acctest.RandInt()
+	resourceName := "azurerm_automation_account.test"
+	config := testAccAzureRMAutomationAccount_free(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sku.0.name", "Free"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationAccount_skuName(t *testing.T) {
+	ri := acctest.RandInt()
+	resourceName := "azurerm_automation_account.test"
+	config := testAccAzureRMAutomationAccount_skuName(ri, testLocation(), "Basic")
+	updatedConfig := testAccAzureRMAutomationAccount_skuName(ri, testLocation(), "Free")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sku_name", "Basic"),
+					resource.TestCheckResourceAttr(resourceName, "sku.0.name", "Basic"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sku_name", "Free"),
+					resource.TestCheckResourceAttr(resourceName, "sku.0.name", "Free"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationAccount_skuCasing(t *testing.T) {
+	ri := acctest.RandInt()
+	resourceName := "azurerm_automation_account.test"
+	config := testAccAzureRMAutomationAccount_lowerCaseSku(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationAccountDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationAccountExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sku.0.name", "basic"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"sku.0.name"},
+			},
+			{
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: false,
+			},
+		},
+	})
+}
+
 func testCheckAzureRMAutomationAccountDestroy(s *terraform.State) error {
 	conn := testAccProvider.Meta().(*ArmClient).automationAccountClient
 
@@ -111,6 +463,33 @@ func testCheckAzureRMAutomationAccountExists(name string) resource.TestCheckFunc
 	}
 }
 
+// testCheckAzureRMAutomationAccountUpdateSku changes the SKU of the Automation Account outside of Terraform
+func testCheckAzureRMAutomationAccountUpdateSku(name string, sku automation.SkuNameEnum) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		accName := rs.Primary.Attributes["name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+
+		conn := testAccProvider.Meta().(*ArmClient).automationAccountClient
+		parameters := automation.AccountUpdateParameters{
+			AccountUpdateProperties: &automation.AccountUpdateProperties{
+				Sku: &automation.Sku{
+					Name: sku,
+				},
+			},
+		}
+		if _, err := conn.Update(resourceGroup, accName, parameters); err != nil {
+			return fmt.Errorf("Bad: Update on automationAccountClient: %+v", err)
+		}
+
+		return nil
+	}
+}
+
 func testAccAzureRMAutomationAccount_basic(rInt int, location string) string {
 	return fmt.Sprintf(`
 resource "azurerm_resource_group" "test" {
@@ -150,3 +529,79 @@ resource "azurerm_automation_account" "test" {
 }
 `, rInt, location, rInt)
 }
+
+func testAccAzureRMAutomationAccount_free(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+ name = "acctestRG-%d"
+ location = "%s"
+}
+
+resource "azurerm_automation_account" "test" {
+  name                = "acctest-%d"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  sku {
+	name = "Free"
+  }
+}
+`, rInt, location, rInt)
+}
+
+func testAccAzureRMAutomationAccount_lowerCaseSku(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+ name = "acctestRG-%d"
+ location = "%s"
+}
+
+resource "azurerm_automation_account" "test" {
+  name                = "acctest-%d"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  sku {
+	name = "basic"
+  }
+}
+`, rInt, location, rInt)
+}
+
+func testAccAzureRMAutomationAccount_skuName(rInt int, location string, skuName string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_automation_account" "test" {
+  name                = "acctest-%d"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  sku_name            = "%s"
+}
+`, rInt, location, rInt, skuName)
+}
+
+func testAccAzureRMAutomationAccount_ignoreSkuChanges(rInt int, location string, environment string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_automation_account" "test" {
+  name                = "acctest-%d"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  sku_name            = "Basic"
+
+  tags {
+    environment = "%s"
+  }
+
+  lifecycle {
+    ignore_changes = ["sku_name"]
+  }
+}
+`, rInt, location, rInt, environment)
+}
@@ -2,8 +2,11 @@ package azurerm
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
@@ -53,6 +56,59 @@ func TestAccAzureRMAutomationCredential_complete(t *testing.T) {
 	})
 }
 
+func TestAzureRMAutomationCredential_changePasswordAfterImport(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.Automation/automationAccounts/acctestaa/credentials/acctestcred"
+
+	client := automation.NewCredentialClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	client.Sender = testAzureResponseSender(http.StatusOK, fmt.Sprintf(`{"id": %q, "name": "acctestcred", "properties": {"userName": "test_user", "description": ""}}`, id))
+	meta := &ArmClient{
+		automationCredentialClient: client,
+	}
+
+	r := resourceArmAutomationCredential()
+	imported, err := r.Importer.State(r.Data(&terraform.InstanceState{ID: id}), meta)
+	if err != nil {
+		t.Fatalf("Error importing: %+v", err)
+	}
+
+	state, err := r.Refresh(imported[0].State(), meta)
+	if err != nil {
+		t.Fatalf("Error reading the imported Automation Credential: %+v", err)
+	}
+
+	for _, password := range []string{"first_password", "rotated_password"} {
+		rawConfig, err := config.NewRawConfig(map[string]interface{}{
+			"name":                "acctestcred",
+			"resource_group_name": "acctestRG",
+			"account_name":        "acctestaa",
+			"username":            "test_user",
+			"password":            password,
+		})
+		if err != nil {
+			t.Fatalf("Error building the configuration: %+v", err)
+		}
+
+		diff, err := r.Diff(state, terraform.NewResourceConfig(rawConfig))
+		if err != nil {
+			t.Fatalf("Error diffing: %+v", err)
+		}
+		if diff == nil || diff.Attributes["password"] == nil || diff.Attributes["password"].New != password {
+			t.Fatalf("Expected a diff updating the password to %q but got %+v", password, diff)
+		}
+		if diff.RequiresNew() {
+			t.Fatalf("Expected the password to be updated in-place but got %+v", diff)
+		}
+
+		state, err = r.Apply(state, diff, meta)
+		if err != nil {
+			t.Fatalf("Error applying: %+v", err)
+		}
+		if actual := state.Attributes["password"]; actual != password {
+			t.Fatalf("Expected the password %q to be stored in the state but got %q", password, actual)
+		}
+	}
+}
+
 func testCheckAzureRMAutomationCredentialDestroy(s *terraform.State) error {
 	conn := testAccProvider.Meta().(*ArmClient).automationCredentialClient
 
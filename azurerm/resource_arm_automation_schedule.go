@@ -53,6 +53,7 @@ func resourceArmAutomationSchedule() *schema.Resource {
 			"expiry_time": {
 				Type:             schema.TypeString,
 				DiffSuppressFunc: compareDataAsUTCSuppressFunc,
+				ValidateFunc:     validateRFC3339Date,
 				Optional:         true,
 				Computed:         true,
 			},
@@ -92,6 +93,7 @@ func validateStartTime(v interface{}, k string) (ws []string, errors []error) {
 	starttime, tperr := time.Parse(time.RFC3339, v.(string))
 	if tperr != nil {
 		errors = append(errors, fmt.Errorf("Cannot parse %q", k))
+		return
 	}
 
 	u := time.Until(starttime)
@@ -119,13 +121,7 @@ func resourceArmAutomationScheduleCreateUpdate(d *schema.ResourceData, meta inte
 		return fmt.Errorf("Cannot parse start_time: %q", cst)
 	}
 
-	expirytime, teperr := time.Parse(time.RFC3339, cst)
-	if teperr != nil {
-		return fmt.Errorf("Cannot parse expiry_time: %q", cst)
-	}
-
 	stdt := date.Time{Time: starttime}
-	etdt := date.Time{Time: expirytime}
 
 	description := d.Get("description").(string)
 	timezone := d.Get("timezone").(string)
@@ -136,11 +132,21 @@ func resourceArmAutomationScheduleCreateUpdate(d *schema.ResourceData, meta inte
 			Description: &description,
 			Frequency:   freq,
 			StartTime:   &stdt,
-			ExpiryTime:  &etdt,
 			TimeZone:    &timezone,
 		},
 	}
 
+	if v, ok := d.GetOk("expiry_time"); ok {
+		cet := v.(string)
+		expirytime, teperr := time.Parse(time.RFC3339, cet)
+		if teperr != nil {
+			return fmt.Errorf("Cannot parse expiry_time: %q", cet)
+		}
+
+		etdt := date.Time{Time: expirytime}
+		parameters.ScheduleCreateOrUpdateProperties.ExpiryTime = &etdt
+	}
+
 	_, err := client.CreateOrUpdate(resGroup, accName, name, parameters)
 	if err != nil {
 		return err
@@ -185,8 +191,12 @@ func resourceArmAutomationScheduleRead(d *schema.ResourceData, meta interface{})
 	d.Set("account_name", accName)
 	d.Set("frequency", resp.Frequency)
 	d.Set("description", resp.Description)
-	d.Set("start_time", string(resp.StartTime.Format(time.RFC3339)))
-	d.Set("expiry_time", string(resp.ExpiryTime.Format(time.RFC3339)))
+	if v := resp.StartTime; v != nil {
+		d.Set("start_time", v.Format(time.RFC3339))
+	}
+	if v := resp.ExpiryTime; v != nil {
+		d.Set("expiry_time", v.Format(time.RFC3339))
+	}
 	d.Set("timezone", resp.TimeZone)
 	return nil
 }
@@ -31,6 +31,38 @@ func TestAccAzureRMAutomationSchedule_oneTime(t *testing.T) {
 	})
 }
 
+func TestAzureRMAutomationScheduleStartTime_validation(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{
+			Value:    "not-a-date",
+			ErrCount: 1,
+		},
+		{
+			Value:    time.Now().UTC().Add(-1 * time.Hour).Format(time.RFC3339),
+			ErrCount: 1,
+		},
+		{
+			Value:    time.Now().UTC().Add(2 * time.Minute).Format(time.RFC3339),
+			ErrCount: 1,
+		},
+		{
+			Value:    time.Now().UTC().Add(10 * time.Minute).Format(time.RFC3339),
+			ErrCount: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateStartTime(tc.Value, "start_time")
+
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected validateStartTime to trigger '%d' errors for '%s' - got '%d'", tc.ErrCount, tc.Value, len(errors))
+		}
+	}
+}
+
 func testCheckAzureRMAutomationScheduleDestroy(s *terraform.State) error {
 	conn := testAccProvider.Meta().(*ArmClient).automationScheduleClient
 
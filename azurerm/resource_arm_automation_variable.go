@@ -0,0 +1,286 @@
+package azurerm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+const (
+	automationVariableTypeString   = "String"
+	automationVariableTypeInteger  = "Integer"
+	automationVariableTypeBoolean  = "Boolean"
+	automationVariableTypeDateTime = "DateTime"
+)
+
+// DateTime values are stored by the Automation Service in the ASP.NET JSON format, e.g. `\/Date(1514764800000)\/`
+var automationVariableDateTimeRegex = regexp.MustCompile(`^\\?/Date\((-?[0-9]+)\)\\?/$`)
+
+func resourceArmAutomationVariable() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmAutomationVariableCreateUpdate,
+		Read:   resourceArmAutomationVariableRead,
+		Update: resourceArmAutomationVariableCreateUpdate,
+		Delete: resourceArmAutomationVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"resource_group_name": resourceGroupNameSchema(),
+
+			"account_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  automationVariableTypeString,
+				ValidateFunc: validation.StringInSlice([]string{
+					automationVariableTypeString,
+					automationVariableTypeInteger,
+					automationVariableTypeBoolean,
+					automationVariableTypeDateTime,
+				}, false),
+			},
+
+			"value": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressAutomationVariableDateTimeValueDiff,
+			},
+
+			"encrypted": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+				ForceNew: true,
+			},
+
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceArmAutomationVariableCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationVariableClient
+	log.Printf("[INFO] preparing arguments for AzureRM Automation Variable creation.")
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+	accName := d.Get("account_name").(string)
+	variableType := d.Get("type").(string)
+	description := d.Get("description").(string)
+	encrypted := d.Get("encrypted").(bool)
+
+	value, err := expandAzureRmAutomationVariableValue(variableType, d.Get("value").(string))
+	if err != nil {
+		return err
+	}
+
+	parameters := automation.VariableCreateOrUpdateParameters{
+		Name: &name,
+		VariableCreateOrUpdateProperties: &automation.VariableCreateOrUpdateProperties{
+			Value:       &value,
+			Description: &description,
+			IsEncrypted: &encrypted,
+		},
+	}
+
+	_, err = client.CreateOrUpdate(resGroup, accName, name, parameters)
+	if err != nil {
+		return fmt.Errorf("Error creating/updating Automation Variable %q (Account %q / Resource Group %q): %+v", name, accName, resGroup, err)
+	}
+
+	read, err := client.Get(resGroup, accName, name)
+	if err != nil {
+		return err
+	}
+
+	if read.ID == nil {
+		return fmt.Errorf("Cannot read Automation Variable '%s' (resource group %s) ID", name, resGroup)
+	}
+
+	d.SetId(*read.ID)
+
+	return resourceArmAutomationVariableRead(d, meta)
+}
+
+func resourceArmAutomationVariableRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationVariableClient
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	accName := id.Path["automationAccounts"]
+	name := id.Path["variables"]
+
+	resp, err := client.Get(resGroup, accName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error making Read request on AzureRM Automation Variable '%s': %+v", name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGroup)
+	d.Set("account_name", accName)
+
+	if props := resp.VariableProperties; props != nil {
+		d.Set("description", props.Description)
+
+		encrypted := false
+		if v := props.IsEncrypted; v != nil {
+			encrypted = *v
+		}
+		d.Set("encrypted", encrypted)
+
+		// the value of an encrypted variable isn't returned from the API, so we keep what's in the config
+		if !encrypted && props.Value != nil {
+			variableType, value, err := flattenAzureRmAutomationVariableValue(*props.Value)
+			if err != nil {
+				return fmt.Errorf("Error flattening the value of Automation Variable %q (Account %q / Resource Group %q): %+v", name, accName, resGroup, err)
+			}
+
+			d.Set("type", variableType)
+			d.Set("value", value)
+		}
+	}
+
+	return nil
+}
+
+func resourceArmAutomationVariableDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationVariableClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	accName := id.Path["automationAccounts"]
+	name := id.Path["variables"]
+
+	resp, err := client.Delete(resGroup, accName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp) {
+			return nil
+		}
+
+		return fmt.Errorf("Error issuing AzureRM delete request for Automation Variable '%s': %+v", name, err)
+	}
+
+	return nil
+}
+
+// suppressAutomationVariableDateTimeValueDiff suppresses the differences in the formatting of a DateTime `value`.
+// Values of the other types are compared as they are.
+func suppressAutomationVariableDateTimeValueDiff(k, old, new string, d *schema.ResourceData) bool {
+	if d.Get("type").(string) != automationVariableTypeDateTime {
+		return false
+	}
+
+	return compareAutomationVariableDateTimeSuppressFunc(k, old, new, d)
+}
+
+// compareAutomationVariableDateTimeSuppressFunc compares two RFC3339 DateTimes to the millisecond, since that's the
+// precision the Automation Service stores them with - so sub-millisecond differences don't cause a perpetual diff
+func compareAutomationVariableDateTimeSuppressFunc(k, old, new string, d *schema.ResourceData) bool {
+	ot, oerr := time.Parse(time.RFC3339, old)
+	nt, nerr := time.Parse(time.RFC3339, new)
+	if oerr != nil || nerr != nil {
+		return false
+	}
+
+	return nt.Truncate(time.Millisecond).Equal(ot.Truncate(time.Millisecond))
+}
+
+// expandAzureRmAutomationVariableValue converts the user-facing value into the JSON-encoded form
+// which the Automation Service uses to store (and infer the type of) a Variable
+func expandAzureRmAutomationVariableValue(variableType string, input string) (string, error) {
+	switch variableType {
+	case automationVariableTypeInteger:
+		i, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("Error parsing %q as an Integer: %+v", input, err)
+		}
+		return strconv.FormatInt(i, 10), nil
+
+	case automationVariableTypeBoolean:
+		b, err := strconv.ParseBool(input)
+		if err != nil {
+			return "", fmt.Errorf("Error parsing %q as a Boolean: %+v", input, err)
+		}
+		return strconv.FormatBool(b), nil
+
+	case automationVariableTypeDateTime:
+		t, err := time.Parse(time.RFC3339, input)
+		if err != nil {
+			return "", fmt.Errorf("Error parsing %q as an RFC3339 DateTime: %+v", input, err)
+		}
+		millis := t.UnixNano() / int64(time.Millisecond)
+		return fmt.Sprintf(`"\/Date(%d)\/"`, millis), nil
+	}
+
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("Error encoding %q as a String: %+v", input, err)
+	}
+	return string(encoded), nil
+}
+
+// flattenAzureRmAutomationVariableValue infers the type of a JSON-encoded Variable value
+// and returns it along with the user-facing value
+func flattenAzureRmAutomationVariableValue(input string) (string, string, error) {
+	if input == "true" || input == "false" {
+		return automationVariableTypeBoolean, input, nil
+	}
+
+	if i, err := strconv.ParseInt(input, 10, 64); err == nil {
+		return automationVariableTypeInteger, strconv.FormatInt(i, 10), nil
+	}
+
+	var s string
+	if err := json.Unmarshal([]byte(input), &s); err != nil {
+		return "", "", fmt.Errorf("Error decoding %q: %+v", input, err)
+	}
+
+	if matches := automationVariableDateTimeRegex.FindStringSubmatch(strings.TrimSpace(s)); len(matches) == 2 {
+		millis, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			return "", "", fmt.Errorf("Error parsing DateTime %q: %+v", s, err)
+		}
+
+		// the fractional seconds are only included when there are some, so that whole seconds read back as they were written
+		t := time.Unix(0, millis*int64(time.Millisecond)).UTC()
+		return automationVariableTypeDateTime, t.Format(time.RFC3339Nano), nil
+	}
+
+	return automationVariableTypeString, s, nil
+}
@@ -0,0 +1,30 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceArmAutomationVariableBool() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmAutomationVariableBoolCreateUpdate,
+		Read:   resourceArmAutomationVariableBoolRead,
+		Update: resourceArmAutomationVariableBoolCreateUpdate,
+		Delete: resourceArmAutomationVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: automationVariableTypedSchema(&schema.Schema{
+			Type:     schema.TypeBool,
+			Required: true,
+		}),
+	}
+}
+
+func resourceArmAutomationVariableBoolCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedCreateUpdate(d, meta, automationVariableTypeBoolean)
+}
+
+func resourceArmAutomationVariableBoolRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedRead(d, meta, automationVariableTypeBoolean)
+}
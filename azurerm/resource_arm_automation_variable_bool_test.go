@@ -0,0 +1,88 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccAzureRMAutomationVariableBool_basic(t *testing.T) {
+	resourceName := "azurerm_automation_variable_bool.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMAutomationVariableBool_basic(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationVariableBool_update(t *testing.T) {
+	resourceName := "azurerm_automation_variable_bool.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMAutomationVariableBool_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "false"),
+				),
+			},
+			{
+				Config: testAccAzureRMAutomationVariableBool_updated(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "true"),
+					resource.TestCheckResourceAttr(resourceName, "description", "This is a test variable for terraform acceptance test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAzureRMAutomationVariableBool_basic(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_bool" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = false
+}
+`, template, rInt)
+}
+
+func testAccAzureRMAutomationVariableBool_updated(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_bool" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = true
+  description         = "This is a test variable for terraform acceptance test"
+}
+`, template, rInt)
+}
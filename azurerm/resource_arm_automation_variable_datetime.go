@@ -0,0 +1,32 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceArmAutomationVariableDateTime() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmAutomationVariableDateTimeCreateUpdate,
+		Read:   resourceArmAutomationVariableDateTimeRead,
+		Update: resourceArmAutomationVariableDateTimeCreateUpdate,
+		Delete: resourceArmAutomationVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: automationVariableTypedSchema(&schema.Schema{
+			Type:             schema.TypeString,
+			Required:         true,
+			DiffSuppressFunc: compareAutomationVariableDateTimeSuppressFunc,
+			ValidateFunc:     validateRFC3339Date,
+		}),
+	}
+}
+
+func resourceArmAutomationVariableDateTimeCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedCreateUpdate(d, meta, automationVariableTypeDateTime)
+}
+
+func resourceArmAutomationVariableDateTimeRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedRead(d, meta, automationVariableTypeDateTime)
+}
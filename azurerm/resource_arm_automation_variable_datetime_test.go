@@ -0,0 +1,88 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccAzureRMAutomationVariableDateTime_basic(t *testing.T) {
+	resourceName := "azurerm_automation_variable_datetime.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMAutomationVariableDateTime_basic(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "2018-01-01T00:00:00Z"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationVariableDateTime_update(t *testing.T) {
+	resourceName := "azurerm_automation_variable_datetime.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMAutomationVariableDateTime_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "2018-01-01T00:00:00Z"),
+				),
+			},
+			{
+				Config: testAccAzureRMAutomationVariableDateTime_updated(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "2019-06-30T12:30:00Z"),
+					resource.TestCheckResourceAttr(resourceName, "description", "This is a test variable for terraform acceptance test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAzureRMAutomationVariableDateTime_basic(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_datetime" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "2018-01-01T00:00:00Z"
+}
+`, template, rInt)
+}
+
+func testAccAzureRMAutomationVariableDateTime_updated(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_datetime" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "2019-06-30T12:30:00Z"
+  description         = "This is a test variable for terraform acceptance test"
+}
+`, template, rInt)
+}
@@ -0,0 +1,30 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceArmAutomationVariableInt() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmAutomationVariableIntCreateUpdate,
+		Read:   resourceArmAutomationVariableIntRead,
+		Update: resourceArmAutomationVariableIntCreateUpdate,
+		Delete: resourceArmAutomationVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: automationVariableTypedSchema(&schema.Schema{
+			Type:     schema.TypeInt,
+			Required: true,
+		}),
+	}
+}
+
+func resourceArmAutomationVariableIntCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedCreateUpdate(d, meta, automationVariableTypeInteger)
+}
+
+func resourceArmAutomationVariableIntRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedRead(d, meta, automationVariableTypeInteger)
+}
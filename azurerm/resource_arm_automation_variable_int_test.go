@@ -0,0 +1,88 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccAzureRMAutomationVariableInt_basic(t *testing.T) {
+	resourceName := "azurerm_automation_variable_int.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMAutomationVariableInt_basic(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "1234"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationVariableInt_update(t *testing.T) {
+	resourceName := "azurerm_automation_variable_int.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMAutomationVariableInt_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "1234"),
+				),
+			},
+			{
+				Config: testAccAzureRMAutomationVariableInt_updated(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "5678"),
+					resource.TestCheckResourceAttr(resourceName, "description", "This is a test variable for terraform acceptance test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAzureRMAutomationVariableInt_basic(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_int" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = 1234
+}
+`, template, rInt)
+}
+
+func testAccAzureRMAutomationVariableInt_updated(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_int" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = 5678
+  description         = "This is a test variable for terraform acceptance test"
+}
+`, template, rInt)
+}
@@ -0,0 +1,31 @@
+package azurerm
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceArmAutomationVariableString() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmAutomationVariableStringCreateUpdate,
+		Read:   resourceArmAutomationVariableStringRead,
+		Update: resourceArmAutomationVariableStringCreateUpdate,
+		Delete: resourceArmAutomationVariableDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: automationVariableTypedSchema(&schema.Schema{
+			Type:      schema.TypeString,
+			Required:  true,
+			Sensitive: true,
+		}),
+	}
+}
+
+func resourceArmAutomationVariableStringCreateUpdate(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedCreateUpdate(d, meta, automationVariableTypeString)
+}
+
+func resourceArmAutomationVariableStringRead(d *schema.ResourceData, meta interface{}) error {
+	return automationVariableTypedRead(d, meta, automationVariableTypeString)
+}
@@ -0,0 +1,88 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+func TestAccAzureRMAutomationVariableString_basic(t *testing.T) {
+	resourceName := "azurerm_automation_variable_string.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMAutomationVariableString_basic(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "Hello, Terraform"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationVariableString_update(t *testing.T) {
+	resourceName := "azurerm_automation_variable_string.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMAutomationVariableString_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "Hello, Terraform"),
+				),
+			},
+			{
+				Config: testAccAzureRMAutomationVariableString_updated(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", "Hello, Automation"),
+					resource.TestCheckResourceAttr(resourceName, "description", "This is a test variable for terraform acceptance test"),
+				),
+			},
+		},
+	})
+}
+
+func testAccAzureRMAutomationVariableString_basic(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_string" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "Hello, Terraform"
+}
+`, template, rInt)
+}
+
+func testAccAzureRMAutomationVariableString_updated(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable_string" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "Hello, Automation"
+  description         = "This is a test variable for terraform acceptance test"
+}
+`, template, rInt)
+}
@@ -0,0 +1,335 @@
+package azurerm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func TestAzureRMAutomationVariableValue_roundTrip(t *testing.T) {
+	cases := []struct {
+		Type    string
+		Value   string
+		Encoded string
+	}{
+		{
+			Type:    "String",
+			Value:   "Hello, Terraform",
+			Encoded: `"Hello, Terraform"`,
+		},
+		{
+			Type:    "String",
+			Value:   "1234",
+			Encoded: `"1234"`,
+		},
+		{
+			Type:    "Integer",
+			Value:   "1234",
+			Encoded: `1234`,
+		},
+		{
+			Type:    "Boolean",
+			Value:   "true",
+			Encoded: `true`,
+		},
+		{
+			Type:    "DateTime",
+			Value:   "2018-01-01T00:00:00Z",
+			Encoded: `"\/Date(1514764800000)\/"`,
+		},
+		{
+			Type:    "DateTime",
+			Value:   "2018-01-01T00:00:00.25Z",
+			Encoded: `"\/Date(1514764800250)\/"`,
+		},
+	}
+
+	for _, tc := range cases {
+		encoded, err := expandAzureRmAutomationVariableValue(tc.Type, tc.Value)
+		if err != nil {
+			t.Fatalf("Error expanding %s %q: %+v", tc.Type, tc.Value, err)
+		}
+
+		if encoded != tc.Encoded {
+			t.Fatalf("Expected %s %q to be encoded as %q - got %q", tc.Type, tc.Value, tc.Encoded, encoded)
+		}
+
+		variableType, value, err := flattenAzureRmAutomationVariableValue(encoded)
+		if err != nil {
+			t.Fatalf("Error flattening %q: %+v", encoded, err)
+		}
+
+		if variableType != tc.Type || value != tc.Value {
+			t.Fatalf("Expected %q to be flattened to %s %q - got %s %q", encoded, tc.Type, tc.Value, variableType, value)
+		}
+	}
+}
+
+func TestAzureRMAutomationVariableValue_diffSuppress(t *testing.T) {
+	cases := []struct {
+		Type     string
+		Old      string
+		New      string
+		Suppress bool
+	}{
+		{
+			Type:     "DateTime",
+			Old:      "2018-01-01T00:00:00Z",
+			New:      "2018-01-01T01:00:00+01:00",
+			Suppress: true,
+		},
+		{
+			// the Automation Service only stores DateTimes to the millisecond
+			Type:     "DateTime",
+			Old:      "2018-01-01T00:00:00.123Z",
+			New:      "2018-01-01T00:00:00.123456Z",
+			Suppress: true,
+		},
+		{
+			Type:     "DateTime",
+			Old:      "2018-01-01T00:00:00Z",
+			New:      "2018-01-01T00:00:00.5Z",
+			Suppress: false,
+		},
+		{
+			// values which aren't DateTimes are never compared as one
+			Type:     "String",
+			Old:      "2018-01-01T00:00:00Z",
+			New:      "2018-01-01T01:00:00+01:00",
+			Suppress: false,
+		},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceArmAutomationVariable().Schema, map[string]interface{}{
+			"type": tc.Type,
+		})
+
+		if actual := suppressAutomationVariableDateTimeValueDiff("value", tc.Old, tc.New, d); actual != tc.Suppress {
+			t.Fatalf("Expected the diff between %s %q and %q to be suppressed %t but got %t", tc.Type, tc.Old, tc.New, tc.Suppress, actual)
+		}
+	}
+}
+
+func TestAzureRMAutomationVariableValue_invalid(t *testing.T) {
+	cases := []struct {
+		Type  string
+		Value string
+	}{
+		{
+			Type:  "Integer",
+			Value: "hello",
+		},
+		{
+			Type:  "Boolean",
+			Value: "maybe",
+		},
+		{
+			Type:  "DateTime",
+			Value: "2018-01-01",
+		},
+	}
+
+	for _, tc := range cases {
+		if _, err := expandAzureRmAutomationVariableValue(tc.Type, tc.Value); err == nil {
+			t.Fatalf("Expected an error expanding %s %q but didn't get one", tc.Type, tc.Value)
+		}
+	}
+}
+
+func TestAccAzureRMAutomationVariable_string(t *testing.T) {
+	resourceName := "azurerm_automation_variable.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMAutomationVariable_string(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "type", "String"),
+					resource.TestCheckResourceAttr(resourceName, "value", "Hello, Terraform"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationVariable_integer(t *testing.T) {
+	resourceName := "azurerm_automation_variable.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMAutomationVariable_integer(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "type", "Integer"),
+					resource.TestCheckResourceAttr(resourceName, "value", "1234"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationVariable_encrypted(t *testing.T) {
+	resourceName := "azurerm_automation_variable.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMAutomationVariable_encrypted(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "encrypted", "true"),
+					resource.TestCheckResourceAttr(resourceName, "value", "s3cr3t"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckAzureRMAutomationVariableDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*ArmClient).automationVariableClient
+
+	for _, rs := range s.RootModule().Resources {
+		// this also covers the typed Variables, e.g. `azurerm_automation_variable_int`
+		if !strings.HasPrefix(rs.Type, "azurerm_automation_variable") {
+			continue
+		}
+
+		name := rs.Primary.Attributes["name"]
+		accName := rs.Primary.Attributes["account_name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+
+		resp, err := conn.Get(resourceGroup, accName, name)
+
+		if err != nil {
+			if utils.ResponseWasNotFound(resp.Response) {
+				return nil
+			}
+
+			return err
+		}
+
+		return fmt.Errorf("Automation Variable still exists:\n%#v", resp)
+	}
+
+	return nil
+}
+
+func testCheckAzureRMAutomationVariableExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		// Ensure we have enough information in state to look up in API
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		name := rs.Primary.Attributes["name"]
+		accName := rs.Primary.Attributes["account_name"]
+
+		resourceGroup, hasResourceGroup := rs.Primary.Attributes["resource_group_name"]
+		if !hasResourceGroup {
+			return fmt.Errorf("Bad: no resource group found in state for Automation Variable: '%s'", name)
+		}
+
+		conn := testAccProvider.Meta().(*ArmClient).automationVariableClient
+
+		resp, err := conn.Get(resourceGroup, accName, name)
+
+		if err != nil {
+			if utils.ResponseWasNotFound(resp.Response) {
+				return fmt.Errorf("Automation Variable '%s' (resource group: '%s') does not exist", name, resourceGroup)
+			}
+
+			return fmt.Errorf("Bad: Get on automationVariableClient: %+v", err)
+		}
+
+		return nil
+	}
+}
+
+func testAccAzureRMAutomationVariable_template(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_automation_account" "test" {
+  name                = "acctest-%d"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+
+  sku {
+    name = "Basic"
+  }
+}
+`, rInt, location, rInt)
+}
+
+func testAccAzureRMAutomationVariable_string(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "Hello, Terraform"
+  description         = "This is a test variable for terraform acceptance test"
+}
+`, template, rInt)
+}
+
+func testAccAzureRMAutomationVariable_integer(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  type                = "Integer"
+  value               = "1234"
+}
+`, template, rInt)
+}
+
+func testAccAzureRMAutomationVariable_encrypted(rInt int, location string) string {
+	template := testAccAzureRMAutomationVariable_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_variable" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  value               = "s3cr3t"
+  encrypted           = true
+}
+`, template, rInt)
+}
@@ -0,0 +1,256 @@
+package azurerm
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/arm/automation"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func resourceArmAutomationWebhook() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmAutomationWebhookCreate,
+		Read:   resourceArmAutomationWebhookRead,
+		Update: resourceArmAutomationWebhookUpdate,
+		Delete: resourceArmAutomationWebhookDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"resource_group_name": resourceGroupNameSchema(),
+
+			"account_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"runbook_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"expiry_time": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: compareDataAsUTCSuppressFunc,
+				ValidateFunc:     validateRFC3339Date,
+			},
+
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+
+			"parameters": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+
+			"run_on": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			// the URI is only available when the Webhook is created, so it's kept in the state
+			"uri": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func resourceArmAutomationWebhookCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationWebhookClient
+	log.Printf("[INFO] preparing arguments for AzureRM Automation Webhook creation.")
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+	accName := d.Get("account_name").(string)
+	runbookName := d.Get("runbook_name").(string)
+	enabled := d.Get("enabled").(bool)
+	runOn := d.Get("run_on").(string)
+
+	expiryTime, err := time.Parse(time.RFC3339, d.Get("expiry_time").(string))
+	if err != nil {
+		return fmt.Errorf("Cannot parse expiry_time: %q", d.Get("expiry_time").(string))
+	}
+
+	uri, err := client.GenerateURI(resGroup, accName)
+	if err != nil {
+		return fmt.Errorf("Error generating the URI for Automation Webhook %q (Account %q / Resource Group %q): %+v", name, accName, resGroup, err)
+	}
+
+	if uri.Value == nil {
+		return fmt.Errorf("Error generating the URI for Automation Webhook %q (Account %q / Resource Group %q): no URI was returned", name, accName, resGroup)
+	}
+
+	parameters := automation.WebhookCreateOrUpdateParameters{
+		Name: &name,
+		WebhookCreateOrUpdateProperties: &automation.WebhookCreateOrUpdateProperties{
+			IsEnabled:  &enabled,
+			URI:        uri.Value,
+			ExpiryTime: &date.Time{Time: expiryTime},
+			Parameters: expandAzureRmAutomationWebhookParameters(d.Get("parameters").(map[string]interface{})),
+			Runbook: &automation.RunbookAssociationProperty{
+				Name: &runbookName,
+			},
+		},
+	}
+
+	if runOn != "" {
+		parameters.WebhookCreateOrUpdateProperties.RunOn = &runOn
+	}
+
+	_, err = client.CreateOrUpdate(resGroup, accName, name, parameters)
+	if err != nil {
+		return fmt.Errorf("Error creating Automation Webhook %q (Account %q / Resource Group %q): %+v", name, accName, resGroup, err)
+	}
+
+	read, err := client.Get(resGroup, accName, name)
+	if err != nil {
+		return err
+	}
+
+	if read.ID == nil {
+		return fmt.Errorf("Cannot read Automation Webhook '%s' (resource group %s) ID", name, resGroup)
+	}
+
+	d.SetId(*read.ID)
+	d.Set("uri", uri.Value)
+
+	return resourceArmAutomationWebhookRead(d, meta)
+}
+
+func resourceArmAutomationWebhookUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationWebhookClient
+	log.Printf("[INFO] preparing arguments for AzureRM Automation Webhook update.")
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+	accName := d.Get("account_name").(string)
+	enabled := d.Get("enabled").(bool)
+	runOn := d.Get("run_on").(string)
+
+	parameters := automation.WebhookUpdateParameters{
+		Name: &name,
+		WebhookUpdateProperties: &automation.WebhookUpdateProperties{
+			IsEnabled:  &enabled,
+			RunOn:      &runOn,
+			Parameters: expandAzureRmAutomationWebhookParameters(d.Get("parameters").(map[string]interface{})),
+		},
+	}
+
+	_, err := client.Update(resGroup, accName, name, parameters)
+	if err != nil {
+		return fmt.Errorf("Error updating Automation Webhook %q (Account %q / Resource Group %q): %+v", name, accName, resGroup, err)
+	}
+
+	return resourceArmAutomationWebhookRead(d, meta)
+}
+
+func resourceArmAutomationWebhookRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationWebhookClient
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	accName := id.Path["automationAccounts"]
+	name := id.Path["webhooks"]
+
+	resp, err := client.Get(resGroup, accName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error making Read request on AzureRM Automation Webhook '%s': %+v", name, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resGroup)
+	d.Set("account_name", accName)
+
+	if props := resp.WebhookProperties; props != nil {
+		d.Set("enabled", props.IsEnabled)
+		d.Set("run_on", props.RunOn)
+
+		if v := props.ExpiryTime; v != nil {
+			d.Set("expiry_time", v.Format(time.RFC3339))
+		}
+
+		if runbook := props.Runbook; runbook != nil {
+			d.Set("runbook_name", runbook.Name)
+		}
+
+		if err := d.Set("parameters", flattenAzureRmAutomationWebhookParameters(props.Parameters)); err != nil {
+			return fmt.Errorf("Error setting `parameters`: %+v", err)
+		}
+	}
+
+	return nil
+}
+
+func resourceArmAutomationWebhookDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).automationWebhookClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resGroup := id.ResourceGroup
+	accName := id.Path["automationAccounts"]
+	name := id.Path["webhooks"]
+
+	resp, err := client.Delete(resGroup, accName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp) {
+			return nil
+		}
+
+		return fmt.Errorf("Error issuing AzureRM delete request for Automation Webhook '%s': %+v", name, err)
+	}
+
+	return nil
+}
+
+func expandAzureRmAutomationWebhookParameters(input map[string]interface{}) *map[string]*string {
+	output := make(map[string]*string, len(input))
+	for k, v := range input {
+		value := v.(string)
+		output[k] = &value
+	}
+	return &output
+}
+
+func flattenAzureRmAutomationWebhookParameters(input *map[string]*string) map[string]interface{} {
+	output := make(map[string]interface{})
+	if input == nil {
+		return output
+	}
+
+	for k, v := range *input {
+		if v != nil {
+			output[k] = *v
+		}
+	}
+	return output
+}
@@ -0,0 +1,218 @@
+package azurerm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func TestAzureRMAutomationWebhookParameters_roundTrip(t *testing.T) {
+	input := map[string]interface{}{
+		"environment": "production",
+		"empty":       "",
+	}
+
+	expanded := expandAzureRmAutomationWebhookParameters(input)
+	if len(*expanded) != len(input) {
+		t.Fatalf("Expected %d expanded parameters but got %d", len(input), len(*expanded))
+	}
+
+	flattened := flattenAzureRmAutomationWebhookParameters(expanded)
+	for k, v := range input {
+		if flattened[k] != v {
+			t.Fatalf("Expected the parameter %q to be %q but got %q", k, v, flattened[k])
+		}
+	}
+
+	if output := flattenAzureRmAutomationWebhookParameters(nil); len(output) != 0 {
+		t.Fatalf("Expected no parameters when flattening nil but got %+v", output)
+	}
+}
+
+func TestAccAzureRMAutomationWebhook_basic(t *testing.T) {
+	resourceName := "azurerm_automation_webhook.test"
+	ri := acctest.RandInt()
+	expiryTime := time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+	config := testAccAzureRMAutomationWebhook_basic(ri, testLocation(), expiryTime)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationWebhookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationWebhookExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "uri"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMAutomationWebhook_update(t *testing.T) {
+	resourceName := "azurerm_automation_webhook.test"
+	ri := acctest.RandInt()
+	expiryTime := time.Now().UTC().Add(24 * time.Hour).Format(time.RFC3339)
+	config := testAccAzureRMAutomationWebhook_basic(ri, testLocation(), expiryTime)
+	updatedConfig := testAccAzureRMAutomationWebhook_complete(ri, testLocation(), expiryTime)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMAutomationWebhookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationWebhookExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "true"),
+					resource.TestCheckResourceAttr(resourceName, "parameters.%", "0"),
+				),
+			},
+			{
+				Config: updatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMAutomationWebhookExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "enabled", "false"),
+					resource.TestCheckResourceAttr(resourceName, "parameters.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "parameters.vmname", "vm1"),
+					resource.TestCheckResourceAttrSet(resourceName, "uri"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckAzureRMAutomationWebhookDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*ArmClient).automationWebhookClient
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "azurerm_automation_webhook" {
+			continue
+		}
+
+		name := rs.Primary.Attributes["name"]
+		accName := rs.Primary.Attributes["account_name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+
+		resp, err := conn.Get(resourceGroup, accName, name)
+
+		if err != nil {
+			if utils.ResponseWasNotFound(resp.Response) {
+				return nil
+			}
+
+			return err
+		}
+
+		return fmt.Errorf("Automation Webhook still exists:\n%#v", resp)
+	}
+
+	return nil
+}
+
+func testCheckAzureRMAutomationWebhookExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		// Ensure we have enough information in state to look up in API
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		name := rs.Primary.Attributes["name"]
+		accName := rs.Primary.Attributes["account_name"]
+
+		resourceGroup, hasResourceGroup := rs.Primary.Attributes["resource_group_name"]
+		if !hasResourceGroup {
+			return fmt.Errorf("Bad: no resource group found in state for Automation Webhook: '%s'", name)
+		}
+
+		conn := testAccProvider.Meta().(*ArmClient).automationWebhookClient
+
+		resp, err := conn.Get(resourceGroup, accName, name)
+
+		if err != nil {
+			if utils.ResponseWasNotFound(resp.Response) {
+				return fmt.Errorf("Automation Webhook '%s' (resource group: '%s') does not exist", name, resourceGroup)
+			}
+
+			return fmt.Errorf("Bad: Get on automationWebhookClient: %+v", err)
+		}
+
+		return nil
+	}
+}
+
+func testAccAzureRMAutomationWebhook_template(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_automation_account" "test" {
+  name                = "acctest-%d"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  sku_name            = "Basic"
+}
+
+resource "azurerm_automation_runbook" "test" {
+  name                = "Get-AzureVMTutorial"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  log_verbose         = "true"
+  log_progress        = "true"
+  description         = "This is a test runbook for terraform acceptance test"
+  runbook_type        = "PowerShellWorkflow"
+
+  publish_content_link {
+    uri = "https://raw.githubusercontent.com/Azure/azure-quickstart-templates/master/101-automation-runbook-getvms/Runbooks/Get-AzureVMTutorial.ps1"
+  }
+}
+`, rInt, location, rInt)
+}
+
+func testAccAzureRMAutomationWebhook_basic(rInt int, location string, expiryTime string) string {
+	template := testAccAzureRMAutomationWebhook_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_webhook" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  runbook_name        = "${azurerm_automation_runbook.test.name}"
+  expiry_time         = "%s"
+}
+`, template, rInt, expiryTime)
+}
+
+func testAccAzureRMAutomationWebhook_complete(rInt int, location string, expiryTime string) string {
+	template := testAccAzureRMAutomationWebhook_template(rInt, location)
+	return fmt.Sprintf(`
+%s
+
+resource "azurerm_automation_webhook" "test" {
+  name                = "acctest-%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  account_name        = "${azurerm_automation_account.test.name}"
+  runbook_name        = "${azurerm_automation_runbook.test.name}"
+  expiry_time         = "%s"
+  enabled             = false
+
+  parameters {
+    vmname = "vm1"
+  }
+}
+`, template, rInt, expiryTime)
+}
@@ -1,15 +1,20 @@
 package azurerm
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
 	"regexp"
-
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/arm/containerregistry"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/response"
 	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
 )
 
@@ -25,6 +30,11 @@ func resourceArmContainerRegistry() *schema.Resource {
 		MigrateState:  resourceAzureRMContainerRegistryMigrateState,
 		SchemaVersion: 2,
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
@@ -74,10 +84,46 @@ func resourceArmContainerRegistry() *schema.Resource {
 							Required: true,
 						},
 
+						// not ForceNew, since the key isn't sent to Azure (the Registry is only given the Storage Account's ID)
 						"access_key": {
-							Type:      schema.TypeString,
-							Required:  true,
-							Sensitive: true,
+							Type:         schema.TypeString,
+							Required:     true,
+							Sensitive:    true,
+							ValidateFunc: validateStorageAccountAccessKey,
+						},
+					},
+				},
+			},
+
+			"georeplication_locations": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:             schema.TypeString,
+					StateFunc:        azureRMNormalizeLocation,
+					DiffSuppressFunc: azureRMSuppressLocationDiff,
+				},
+				Set: resourceAzureRMContainerRegistryGeoReplicationLocationHash,
+			},
+
+			"replications": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"location": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+
+						"provisioning_state": {
+							Type:     schema.TypeString,
+							Computed: true,
 						},
 					},
 				},
@@ -88,14 +134,27 @@ func resourceArmContainerRegistry() *schema.Resource {
 				Computed: true,
 			},
 
-			"admin_username": {
+			"status": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 
+			"admin_username": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
 			"admin_password": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+
+			"admin_password2": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
 			},
 
 			"tags": tagsSchema(),
@@ -112,7 +171,16 @@ func resourceArmContainerRegistryCreate(d *schema.ResourceData, meta interface{}
 	location := d.Get("location").(string)
 	sku := d.Get("sku").(string)
 	adminUserEnabled := d.Get("admin_enabled").(bool)
-	tags := d.Get("tags").(map[string]interface{})
+	tags := mergeDefaultTags(meta.(*ArmClient).defaultTags, d.Get("tags").(map[string]interface{}))
+	geoReplicationLocations := d.Get("georeplication_locations").(*schema.Set)
+
+	if err := validateContainerRegistryGeoReplicationSku(sku, geoReplicationLocations); err != nil {
+		return err
+	}
+
+	if err := validateContainerRegistryGeoReplicationLocations(location, geoReplicationLocations); err != nil {
+		return err
+	}
 
 	parameters := containerregistry.Registry{
 		Location: &location,
@@ -140,15 +208,35 @@ func resourceArmContainerRegistryCreate(d *schema.ResourceData, meta interface{}
 		}
 	}
 
+	if _, ok := d.GetOk("storage_account"); ok {
+		if err := validateContainerRegistryStorageAccountSku(sku); err != nil {
+			return err
+		}
+	}
+
+	// Registry names are globally unique, so check this up-front rather than failing part-way through provisioning
+	if err := checkContainerRegistryNameAvailability(client, name); err != nil {
+		return err
+	}
+
 	_, createErr := client.Create(resourceGroup, name, parameters, make(<-chan struct{}))
 	err := <-createErr
 	if err != nil {
+		if wasResourceGroupNotFound(err) {
+			return resourceGroupNotFoundError(resourceGroup, err)
+		}
+
+		return fmt.Errorf("Error creating Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+	}
+
+	// the credentials & replications can't be retrieved until the registry has finished provisioning
+	if err := waitForContainerRegistryToBeAvailable(client, resourceGroup, name, d.Timeout(schema.TimeoutCreate)); err != nil {
 		return err
 	}
 
 	read, err := client.Get(resourceGroup, name)
 	if err != nil {
-		return err
+		return fmt.Errorf("Error retrieving Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
 	}
 
 	if read.ID == nil {
@@ -157,6 +245,13 @@ func resourceArmContainerRegistryCreate(d *schema.ResourceData, meta interface{}
 
 	d.SetId(*read.ID)
 
+	if geoReplicationLocations.Len() > 0 {
+		emptyLocations := &schema.Set{F: resourceAzureRMContainerRegistryGeoReplicationLocationHash}
+		if err := applyContainerRegistryGeoReplicationLocations(meta, resourceGroup, name, emptyLocations, geoReplicationLocations); err != nil {
+			return fmt.Errorf("Error applying Geo-Replication Locations for Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+		}
+	}
+
 	return resourceArmContainerRegistryRead(d, meta)
 }
 
@@ -169,15 +264,31 @@ func resourceArmContainerRegistryUpdate(d *schema.ResourceData, meta interface{}
 
 	sku := d.Get("sku").(string)
 	adminUserEnabled := d.Get("admin_enabled").(bool)
-	tags := d.Get("tags").(map[string]interface{})
+
+	if err := validateContainerRegistryGeoReplicationSku(sku, d.Get("georeplication_locations").(*schema.Set)); err != nil {
+		return err
+	}
+
+	if err := validateContainerRegistryGeoReplicationLocations(d.Get("location").(string), d.Get("georeplication_locations").(*schema.Set)); err != nil {
+		return err
+	}
 
 	parameters := containerregistry.RegistryUpdateParameters{
 		RegistryPropertiesUpdateParameters: &containerregistry.RegistryPropertiesUpdateParameters{
 			AdminUserEnabled: utils.Bool(adminUserEnabled),
 		},
-		Tags: expandTags(tags),
 	}
 
+	// the tags are always sent (rather than only when `tags` changes) so that changes to the provider's `default_tags`
+	// are applied - and since they're replaced as a whole, any hidden tags added by Azure need to be sent back
+	existing, err := client.Get(resourceGroup, name)
+	if err != nil {
+		return fmt.Errorf("Error retrieving Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+	}
+
+	tags := mergeDefaultTags(meta.(*ArmClient).defaultTags, d.Get("tags").(map[string]interface{}))
+	parameters.Tags = expandTags(mergeHiddenTags(tags, existing.Tags, meta.(*ArmClient).hiddenTagPrefix))
+
 	if v, ok := d.GetOk("storage_account_id"); ok {
 		if strings.ToLower(sku) != strings.ToLower(string(containerregistry.Classic)) {
 			return fmt.Errorf("`storage_account_id` can only be specified for a Classic (unmanaged) Sku.")
@@ -192,15 +303,28 @@ func resourceArmContainerRegistryUpdate(d *schema.ResourceData, meta interface{}
 		}
 	}
 
+	if _, ok := d.GetOk("storage_account"); ok {
+		if err := validateContainerRegistryStorageAccountSku(sku); err != nil {
+			return err
+		}
+	}
+
 	_, updateErr := client.Update(resourceGroup, name, parameters, make(chan struct{}))
-	err := <-updateErr
+	err = <-updateErr
 	if err != nil {
-		return err
+		return fmt.Errorf("Error updating Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+	}
+
+	if d.HasChange("georeplication_locations") {
+		old, new := d.GetChange("georeplication_locations")
+		if err := applyContainerRegistryGeoReplicationLocations(meta, resourceGroup, name, old.(*schema.Set), new.(*schema.Set)); err != nil {
+			return fmt.Errorf("Error applying Geo-Replication Locations for Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+		}
 	}
 
 	read, err := client.Get(resourceGroup, name)
 	if err != nil {
-		return err
+		return fmt.Errorf("Error retrieving Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
 	}
 
 	if read.ID == nil {
@@ -214,6 +338,7 @@ func resourceArmContainerRegistryUpdate(d *schema.ResourceData, meta interface{}
 
 func resourceArmContainerRegistryRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*ArmClient).containerRegistryClient
+	replicationClient := meta.(*ArmClient).containerRegistryReplicationsClient
 
 	id, err := parseAzureResourceID(d.Id())
 	if err != nil {
@@ -232,37 +357,99 @@ func resourceArmContainerRegistryRead(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Error making Read request on Azure Container Registry %q: %+v", name, err)
 	}
 
+	// use the casing returned by the API, so that anything scoped to this ID (e.g. Event Grid Subscriptions) matches
+	if resp.ID != nil {
+		d.SetId(*resp.ID)
+	}
+
+	// the name, location and sku are taken from the API (rather than the ID or the config) so that
+	// an imported Registry matches Azure's record of it
 	d.Set("name", resp.Name)
 	d.Set("resource_group_name", resourceGroup)
-	d.Set("location", azureRMNormalizeLocation(*resp.Location))
-	d.Set("admin_enabled", resp.AdminUserEnabled)
-	d.Set("login_server", resp.LoginServer)
+	location := ""
+	if resp.Location != nil {
+		location = azureRMNormalizeLocation(*resp.Location)
+	}
+	d.Set("location", location)
 
 	if sku := resp.Sku; sku != nil {
 		d.Set("sku", string(sku.Tier))
 	}
 
-	if account := resp.StorageAccount; account != nil {
-		d.Set("storage_account_id", account.ID)
+	adminUserEnabled := false
+	status := ""
+	if props := resp.RegistryProperties; props != nil {
+		if props.AdminUserEnabled != nil {
+			adminUserEnabled = *props.AdminUserEnabled
+		}
+		d.Set("admin_enabled", adminUserEnabled)
+
+		if account := props.StorageAccount; account != nil {
+			d.Set("storage_account_id", account.ID)
+		}
+
+		// the Login Server differs between clouds, so it's always taken from the API rather than
+		// being derived from the name - and is available regardless of whether the admin user is enabled
+		d.Set("login_server", props.LoginServer)
+
+		if props.Status != nil && props.Status.DisplayStatus != nil {
+			status = *props.Status.DisplayStatus
+		}
 	}
+	d.Set("status", status)
+
+	if adminUserEnabled {
+		ctx, cancel := context.WithTimeout(meta.(*ArmClient).StopContext, d.Timeout(schema.TimeoutRead))
+		defer cancel()
 
-	if *resp.AdminUserEnabled {
-		credsResp, err := client.ListCredentials(resourceGroup, name)
+		credsResp, err := listContainerRegistryCredentials(ctx, client, resourceGroup, name)
 		if err != nil {
 			return fmt.Errorf("Error making Read request on Azure Container Registry %s for Credentials: %s", name, err)
 		}
 
-		d.Set("admin_username", credsResp.Username)
-		for _, v := range *credsResp.Passwords {
-			d.Set("admin_password", v.Value)
-			break
+		if credsResp != nil {
+			d.Set("admin_username", credsResp.Username)
+			if passwords := credsResp.Passwords; passwords != nil {
+				for _, v := range *passwords {
+					switch v.Name {
+					case containerregistry.Password:
+						d.Set("admin_password", v.Value)
+					case containerregistry.Password2:
+						d.Set("admin_password2", v.Value)
+					}
+				}
+			}
+		} else {
+			d.Set("admin_username", "")
+			d.Set("admin_password", "")
+			d.Set("admin_password2", "")
 		}
 	} else {
 		d.Set("admin_username", "")
 		d.Set("admin_password", "")
+		d.Set("admin_password2", "")
+	}
+
+	// the Replications are always retrieved from the API (rather than the config) so that
+	// any which were created outside of Terraform are populated when the Registry is imported
+	geoReplicationLocations := &schema.Set{F: resourceAzureRMContainerRegistryGeoReplicationLocationHash}
+	replicationStatuses := make([]interface{}, 0)
+	if sku := resp.Sku; sku != nil && strings.EqualFold(string(sku.Tier), string(containerregistry.Premium)) {
+		replications, err := replicationClient.List(resourceGroup, name)
+		if err != nil {
+			return fmt.Errorf("Error making Read request on Azure Container Registry %q for Replications: %+v", name, err)
+		}
+
+		geoReplicationLocations = flattenContainerRegistryGeoReplicationLocations(location, replications.Value)
+		replicationStatuses = flattenContainerRegistryReplications(replications.Value)
 	}
+	d.Set("georeplication_locations", geoReplicationLocations)
 
-	flattenAndSetTags(d, resp.Tags)
+	if err := d.Set("replications", replicationStatuses); err != nil {
+		return fmt.Errorf("Error setting `replications`: %+v", err)
+	}
+
+	flattenAndSetTagsWithDefaults(d, resp.Tags, meta.(*ArmClient).defaultTags, meta.(*ArmClient).hiddenTagPrefix)
 
 	return nil
 }
@@ -290,6 +477,185 @@ func resourceArmContainerRegistryDelete(d *schema.ResourceData, meta interface{}
 	return nil
 }
 
+func validateContainerRegistryStorageAccountSku(sku string) error {
+	if !strings.EqualFold(sku, string(containerregistry.Classic)) {
+		return fmt.Errorf("The `storage_account` block can only be specified for a Classic (unmanaged) Sku - the %q Sku is Managed and provisions its own storage, so doesn't use a Storage Account.", sku)
+	}
+
+	return nil
+}
+
+// checkContainerRegistryNameAvailability returns an error including the reason given by Azure when the name is
+// already in use. Failing to check the name isn't fatal, since the Create will fail anyway if it's unavailable.
+func checkContainerRegistryNameAvailability(client containerregistry.RegistriesClient, name string) error {
+	request := containerregistry.RegistryNameCheckRequest{
+		Name: utils.String(name),
+		Type: utils.String("Microsoft.ContainerRegistry/registries"),
+	}
+	resp, err := client.CheckNameAvailability(request)
+	if err != nil {
+		log.Printf("[WARN] Unable to check the availability of the Container Registry name %q: %+v", name, err)
+		return nil
+	}
+
+	if resp.NameAvailable == nil || *resp.NameAvailable {
+		return nil
+	}
+
+	reason := ""
+	if resp.Reason != nil {
+		reason = *resp.Reason
+	}
+	message := ""
+	if resp.Message != nil {
+		message = *resp.Message
+	}
+
+	return fmt.Errorf("The Container Registry name %q is unavailable (%s): %s - Container Registry names must be globally unique", name, reason, message)
+}
+
+// listContainerRegistryCredentials retrieves the Admin Credentials of the Container Registry. A principal which can
+// manage the Registry isn't necessarily allowed to list its Credentials, in which case `nil` is returned (and
+// a warning logged) so that the Read can continue without them.
+func listContainerRegistryCredentials(ctx context.Context, client containerregistry.RegistriesClient, resourceGroup string, name string) (*containerregistry.RegistryListCredentialsResult, error) {
+	var credsResp containerregistry.RegistryListCredentialsResult
+	err := retryOn429(ctx, func() (*http.Response, error) {
+		var err error
+		credsResp, err = client.ListCredentials(resourceGroup, name)
+		return credsResp.Response.Response, err
+	})
+	if err != nil {
+		if response.WasForbidden(credsResp.Response.Response) {
+			log.Printf("[WARN] Unable to list the Credentials for Container Registry %q (Resource Group %q) since permission was denied - the Admin Credentials will be empty: %+v", name, resourceGroup, err)
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &credsResp, nil
+}
+
+func validateContainerRegistryGeoReplicationSku(sku string, geoReplicationLocations *schema.Set) error {
+	if geoReplicationLocations.Len() > 0 && !strings.EqualFold(sku, string(containerregistry.Premium)) {
+		return fmt.Errorf("`georeplication_locations` can only be specified for a Premium Sku.")
+	}
+
+	return nil
+}
+
+// validateContainerRegistryGeoReplicationLocations ensures the home location of the registry isn't also used
+// as a Geo-Replication Location, since the home Replication is managed by Azure and creating it is rejected
+func validateContainerRegistryGeoReplicationLocations(location string, geoReplicationLocations *schema.Set) error {
+	homeLocation := azureRMNormalizeLocation(location)
+
+	for _, v := range geoReplicationLocations.List() {
+		if azureRMNormalizeLocation(v) == homeLocation {
+			return fmt.Errorf("`georeplication_locations` cannot contain the home location of the Container Registry (%q).", v.(string))
+		}
+	}
+
+	return nil
+}
+
+func applyContainerRegistryGeoReplicationLocations(meta interface{}, resourceGroup string, name string, oldLocations *schema.Set, newLocations *schema.Set) error {
+	client := meta.(*ArmClient).containerRegistryReplicationsClient
+
+	// locations which have been removed from the config
+	for _, v := range oldLocations.Difference(newLocations).List() {
+		location := azureRMNormalizeLocation(v)
+
+		log.Printf("[INFO] Deleting Replication %q for Container Registry %q (Resource Group %q)", location, name, resourceGroup)
+		deleteResp, deleteErr := client.Delete(resourceGroup, name, location, make(chan struct{}))
+		resp := <-deleteResp
+		err := <-deleteErr
+		if err != nil {
+			if !utils.ResponseWasNotFound(resp) {
+				return fmt.Errorf("Error deleting Replication %q: %+v", location, err)
+			}
+		}
+	}
+
+	// locations which have been added to the config
+	for _, v := range newLocations.Difference(oldLocations).List() {
+		location := azureRMNormalizeLocation(v)
+
+		replication := containerregistry.Replication{
+			Location: utils.String(location),
+			Name:     utils.String(location),
+		}
+
+		log.Printf("[INFO] Creating Replication %q for Container Registry %q (Resource Group %q)", location, name, resourceGroup)
+		_, createErr := client.Create(resourceGroup, name, location, replication, make(chan struct{}))
+		if err := <-createErr; err != nil {
+			return fmt.Errorf("Error creating Replication %q: %+v", location, err)
+		}
+	}
+
+	return nil
+}
+
+func flattenContainerRegistryGeoReplicationLocations(location string, replications *[]containerregistry.Replication) *schema.Set {
+	locations := &schema.Set{F: resourceAzureRMContainerRegistryGeoReplicationLocationHash}
+	if replications == nil {
+		return locations
+	}
+
+	for _, replication := range *replications {
+		if replication.Location == nil {
+			continue
+		}
+
+		// the home location is returned as a Replication but isn't user-configurable
+		replicationLocation := azureRMNormalizeLocation(*replication.Location)
+		if replicationLocation != azureRMNormalizeLocation(location) {
+			locations.Add(replicationLocation)
+		}
+	}
+
+	return locations
+}
+
+// flattenContainerRegistryReplications flattens the status of each Replication (including the home location),
+// sorted by location so that the order is consistent between reads
+func flattenContainerRegistryReplications(replications *[]containerregistry.Replication) []interface{} {
+	result := make([]interface{}, 0)
+	if replications == nil {
+		return result
+	}
+
+	for _, replication := range *replications {
+		if replication.Location == nil {
+			continue
+		}
+
+		status := ""
+		provisioningState := ""
+		if props := replication.ReplicationProperties; props != nil {
+			if props.Status != nil && props.Status.DisplayStatus != nil {
+				status = *props.Status.DisplayStatus
+			}
+			provisioningState = string(props.ProvisioningState)
+		}
+
+		result = append(result, map[string]interface{}{
+			"location":           azureRMNormalizeLocation(*replication.Location),
+			"status":             status,
+			"provisioning_state": provisioningState,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].(map[string]interface{})["location"].(string) < result[j].(map[string]interface{})["location"].(string)
+	})
+
+	return result
+}
+
+func resourceAzureRMContainerRegistryGeoReplicationLocationHash(v interface{}) int {
+	return hashcode.String(azureRMNormalizeLocation(v))
+}
+
 func validateAzureRMContainerRegistryName(v interface{}, k string) (ws []string, errors []error) {
 	value := v.(string)
 	if !regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString(value) {
@@ -305,5 +671,35 @@ func validateAzureRMContainerRegistryName(v interface{}, k string) (ws []string,
 		errors = append(errors, fmt.Errorf("%q cannot be longer than 50 characters: %q %d", k, value, len(value)))
 	}
 
+	// the Login Server is always lower-cased by Azure, so image references built from the name won't match it
+	if value != strings.ToLower(value) {
+		ws = append(ws, fmt.Sprintf("%q contains upper-case characters (%q) - Azure lower-cases the Login Server, so consider using %q", k, value, strings.ToLower(value)))
+	}
+
 	return
 }
+
+func waitForContainerRegistryToBeAvailable(client containerregistry.RegistriesClient, resourceGroup string, name string, timeout time.Duration) error {
+	log.Printf("[DEBUG] Waiting for Container Registry %q (Resource Group %q) to become available", name, resourceGroup)
+	stateConf := provisioningStateChangeConf(containerRegistryProvisioningStateGetter(client, resourceGroup, name), timeout)
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for Container Registry %q (Resource Group %q) to become available: %+v", name, resourceGroup, err)
+	}
+
+	return nil
+}
+
+func containerRegistryProvisioningStateGetter(client containerregistry.RegistriesClient, resourceGroup string, name string) provisioningStateGetter {
+	return func() (string, error) {
+		res, err := client.Get(resourceGroup, name)
+		if err != nil {
+			return "", fmt.Errorf("Error retrieving Container Registry %q (Resource Group %q): %+v", name, resourceGroup, err)
+		}
+
+		if props := res.RegistryProperties; props != nil && props.ProvisioningState != "" {
+			return string(props.ProvisioningState), nil
+		}
+
+		return "", fmt.Errorf("Error reading the Provisioning State of Container Registry %q (Resource Group %q)", name, resourceGroup)
+	}
+}
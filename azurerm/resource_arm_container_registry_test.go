@@ -1,20 +1,31 @@
 package azurerm
 
 import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/arm/containerregistry"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
 )
 
 func TestAccAzureRMContainerRegistryName_validation(t *testing.T) {
 	cases := []struct {
-		Value    string
-		ErrCount int
+		Value     string
+		ErrCount  int
+		WarnCount int
 	}{
 		{
 			Value:    "four",
@@ -33,12 +44,14 @@ func TestAccAzureRMContainerRegistryName_validation(t *testing.T) {
 			ErrCount: 1,
 		},
 		{
-			Value:    "helloWorld",
-			ErrCount: 0,
+			Value:     "helloWorld",
+			ErrCount:  0,
+			WarnCount: 1,
 		},
 		{
-			Value:    "helloworld12",
-			ErrCount: 0,
+			Value:     "helloworld12",
+			ErrCount:  0,
+			WarnCount: 0,
 		},
 		{
 			Value:    "hello@world",
@@ -59,11 +72,537 @@ func TestAccAzureRMContainerRegistryName_validation(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		_, errors := validateAzureRMContainerRegistryName(tc.Value, "azurerm_container_registry")
+		warnings, errors := validateAzureRMContainerRegistryName(tc.Value, "azurerm_container_registry")
 
 		if len(errors) != tc.ErrCount {
 			t.Fatalf("Expected the Azure RM Container Registry Name to trigger a validation error: %v", errors)
 		}
+
+		if len(warnings) != tc.WarnCount {
+			t.Fatalf("Expected the Azure RM Container Registry Name %q to trigger %d warnings - got %d: %v", tc.Value, tc.WarnCount, len(warnings), warnings)
+		}
+	}
+}
+
+func TestAzureRMContainerRegistryStorageAccountSku_validation(t *testing.T) {
+	cases := []struct {
+		Sku         string
+		ExpectError bool
+	}{
+		{Sku: "Classic", ExpectError: false},
+		{Sku: "classic", ExpectError: false},
+		{Sku: "Basic", ExpectError: true},
+		{Sku: "Standard", ExpectError: true},
+		{Sku: "Premium", ExpectError: true},
+	}
+
+	for _, tc := range cases {
+		err := validateContainerRegistryStorageAccountSku(tc.Sku)
+
+		if tc.ExpectError && err == nil {
+			t.Fatalf("Expected an error for a `storage_account` block with the %q Sku", tc.Sku)
+		}
+
+		if !tc.ExpectError && err != nil {
+			t.Fatalf("Expected no error for a `storage_account` block with the %q Sku: %+v", tc.Sku, err)
+		}
+	}
+}
+
+func TestAzureRMContainerRegistryGeoReplicationLocations_validation(t *testing.T) {
+	cases := []struct {
+		Location                string
+		GeoReplicationLocations []interface{}
+		ExpectError             bool
+	}{
+		{
+			Location:                "westeurope",
+			GeoReplicationLocations: []interface{}{},
+			ExpectError:             false,
+		},
+		{
+			Location:                "westeurope",
+			GeoReplicationLocations: []interface{}{"eastus", "North Europe"},
+			ExpectError:             false,
+		},
+		{
+			Location:                "westeurope",
+			GeoReplicationLocations: []interface{}{"eastus", "westeurope"},
+			ExpectError:             true,
+		},
+		{
+			Location:                "West Europe",
+			GeoReplicationLocations: []interface{}{"westeurope"},
+			ExpectError:             true,
+		},
+		{
+			Location:                "westeurope",
+			GeoReplicationLocations: []interface{}{"West Europe"},
+			ExpectError:             true,
+		},
+	}
+
+	for _, tc := range cases {
+		locations := schema.NewSet(resourceAzureRMContainerRegistryGeoReplicationLocationHash, tc.GeoReplicationLocations)
+		err := validateContainerRegistryGeoReplicationLocations(tc.Location, locations)
+
+		if tc.ExpectError && err == nil {
+			t.Fatalf("Expected an error for the location %q with the Geo-Replication Locations %+v", tc.Location, tc.GeoReplicationLocations)
+		}
+
+		if !tc.ExpectError && err != nil {
+			t.Fatalf("Expected no error for the location %q with the Geo-Replication Locations %+v: %+v", tc.Location, tc.GeoReplicationLocations, err)
+		}
+	}
+}
+
+func TestAzureRMContainerRegistryGeoReplicationLocations_flatten(t *testing.T) {
+	// a Registry which was imported with two Replications created outside of Terraform
+	replications := []containerregistry.Replication{
+		{Location: utils.String("westeurope")},
+		{Location: utils.String("East US")},
+		{Location: utils.String("northeurope")},
+		{Location: nil},
+	}
+
+	actual := flattenContainerRegistryGeoReplicationLocations("West Europe", &replications)
+	if actual.Len() != 2 {
+		t.Fatalf("Expected 2 Geo-Replication Locations (excluding the home location) but got %d: %+v", actual.Len(), actual.List())
+	}
+
+	// the configuration may use either form of the location, which shouldn't be a diff
+	config := schema.NewSet(resourceAzureRMContainerRegistryGeoReplicationLocationHash, []interface{}{"eastus", "North Europe"})
+	if actual.Difference(config).Len() != 0 || config.Difference(actual).Len() != 0 {
+		t.Fatalf("Expected the Geo-Replication Locations %+v to match the configuration %+v", actual.List(), config.List())
+	}
+
+	if empty := flattenContainerRegistryGeoReplicationLocations("westeurope", nil); empty.Len() != 0 {
+		t.Fatalf("Expected no Geo-Replication Locations but got %+v", empty.List())
+	}
+}
+
+func TestAzureRMContainerRegistryReplications_flatten(t *testing.T) {
+	replications := []containerregistry.Replication{
+		{
+			Location: utils.String("West Europe"),
+			ReplicationProperties: &containerregistry.ReplicationProperties{
+				ProvisioningState: containerregistry.Succeeded,
+				Status: &containerregistry.Status{
+					DisplayStatus: utils.String("Ready"),
+				},
+			},
+		},
+		{
+			Location: utils.String("eastus"),
+			ReplicationProperties: &containerregistry.ReplicationProperties{
+				ProvisioningState: containerregistry.Creating,
+			},
+		},
+		{Location: nil},
+	}
+
+	actual := flattenContainerRegistryReplications(&replications)
+	expected := []interface{}{
+		map[string]interface{}{
+			"location":           "eastus",
+			"status":             "",
+			"provisioning_state": "Creating",
+		},
+		map[string]interface{}{
+			"location":           "westeurope",
+			"status":             "Ready",
+			"provisioning_state": "Succeeded",
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected the Replications to be %+v but got %+v", expected, actual)
+	}
+
+	if empty := flattenContainerRegistryReplications(nil); len(empty) != 0 {
+		t.Fatalf("Expected no Replications but got %+v", empty)
+	}
+}
+
+func TestAzureRMContainerRegistry_importByIdHasNoDiff(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.ContainerRegistry/registries/acctestreg"
+
+	meta := testContainerRegistryMeta(testAzureResponseSender(http.StatusOK, testContainerRegistryJSON(id, "Basic", "")))
+
+	r := resourceArmContainerRegistry()
+	imported, err := r.Importer.State(r.Data(&terraform.InstanceState{ID: id}), meta)
+	if err != nil {
+		t.Fatalf("Error importing: %+v", err)
+	}
+
+	state, err := r.Refresh(imported[0].State(), meta)
+	if err != nil {
+		t.Fatalf("Error reading the imported Container Registry: %+v", err)
+	}
+
+	expected := map[string]string{
+		"name":                "acctestreg",
+		"resource_group_name": "acctestRG",
+		"location":            "westeurope",
+		"sku":                 "Basic",
+		"admin_enabled":       "false",
+	}
+	for k, v := range expected {
+		if actual := state.Attributes[k]; actual != v {
+			t.Fatalf("Expected %q to be %q but got %q", k, v, actual)
+		}
+	}
+
+	rawConfig, err := config.NewRawConfig(map[string]interface{}{
+		"name":                "acctestreg",
+		"resource_group_name": "acctestRG",
+		"location":            "West Europe",
+		"sku":                 "Basic",
+	})
+	if err != nil {
+		t.Fatalf("Error building the configuration: %+v", err)
+	}
+
+	diff, err := r.Diff(state, terraform.NewResourceConfig(rawConfig))
+	if err != nil {
+		t.Fatalf("Error diffing: %+v", err)
+	}
+
+	if !diff.Empty() {
+		t.Fatalf("Expected no diff after importing but got %+v", diff)
+	}
+}
+
+func TestAzureRMContainerRegistry_updateStorageAccountAccessKeyInPlace(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.ContainerRegistry/registries/acctestreg"
+	storageAccountId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.Storage/storageAccounts/acctestsa"
+	oldKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 64))
+	newKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 64))
+
+	sender := testAzureResponseSender(http.StatusOK, testContainerRegistryJSON(id, "Classic", storageAccountId))
+	meta := testContainerRegistryMeta(sender)
+
+	state := &terraform.InstanceState{
+		ID: id,
+		Attributes: map[string]string{
+			"id":                           id,
+			"name":                         "acctestreg",
+			"resource_group_name":          "acctestRG",
+			"location":                     "westeurope",
+			"sku":                          "Classic",
+			"admin_enabled":                "false",
+			"storage_account_id":           storageAccountId,
+			"storage_account.#":            "1",
+			"storage_account.0.name":       "acctestsa",
+			"storage_account.0.access_key": oldKey,
+			"georeplication_locations.#":   "0",
+			"tags.%":                       "0",
+		},
+	}
+
+	rawConfig, err := config.NewRawConfig(map[string]interface{}{
+		"name":                "acctestreg",
+		"resource_group_name": "acctestRG",
+		"location":            "westeurope",
+		"sku":                 "Classic",
+		"storage_account_id":  storageAccountId,
+		"storage_account": []interface{}{
+			map[string]interface{}{
+				"name":       "acctestsa",
+				"access_key": newKey,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error building the configuration: %+v", err)
+	}
+
+	r := resourceArmContainerRegistry()
+	diff, err := r.Diff(state, terraform.NewResourceConfig(rawConfig))
+	if err != nil {
+		t.Fatalf("Error diffing: %+v", err)
+	}
+	if diff == nil || diff.Attributes["storage_account.0.access_key"] == nil {
+		t.Fatalf("Expected a diff for `storage_account.0.access_key` but got %+v", diff)
+	}
+	if diff.RequiresNew() {
+		t.Fatalf("Expected rotating the access key not to recreate the Container Registry but got %+v", diff)
+	}
+
+	updated, err := r.Apply(state, diff, meta)
+	if err != nil {
+		t.Fatalf("Error applying: %+v", err)
+	}
+
+	if updated.ID != id {
+		t.Fatalf("Expected the ID to be unchanged as %q but got %q", id, updated.ID)
+	}
+	if actual := updated.Attributes["storage_account.0.access_key"]; actual != newKey {
+		t.Fatalf("Expected the access key in the state to be %q but got %q", newKey, actual)
+	}
+
+	// the Registry is only ever given the ID of the Storage Account, so the new key is stored in the state but ignored by Azure
+	patched := false
+	for _, request := range sender.Requests {
+		if request.Method == http.MethodPut || request.Method == http.MethodDelete {
+			t.Fatalf("Expected the Container Registry not to be recreated but got a %s to %q", request.Method, request.Path)
+		}
+		if strings.Contains(request.Body, newKey) || strings.Contains(request.Body, oldKey) {
+			t.Fatalf("Expected the access key not to be sent to Azure but got a %s to %q containing it", request.Method, request.Path)
+		}
+		if request.Method == http.MethodPatch {
+			patched = true
+		}
+	}
+	if !patched {
+		t.Fatalf("Expected the Container Registry to be updated in-place but got the requests %+v", sender.Requests)
+	}
+}
+
+func TestAzureRMContainerRegistry_readClearsCredentialsWhenAdminDisabled(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.ContainerRegistry/registries/acctestreg"
+
+	sender := testAzureResponseSender(http.StatusOK, testContainerRegistryJSON(id, "Basic", ""))
+	meta := testContainerRegistryMeta(sender)
+
+	// the admin user has been disabled outside of Terraform, so the credentials in the state are stale
+	state := &terraform.InstanceState{
+		ID: id,
+		Attributes: map[string]string{
+			"id":              id,
+			"name":            "acctestreg",
+			"admin_enabled":   "true",
+			"admin_username":  "acctestreg",
+			"admin_password":  "password",
+			"admin_password2": "password2",
+		},
+		Meta: map[string]interface{}{
+			"schema_version": "2",
+		},
+	}
+
+	r := resourceArmContainerRegistry()
+	refreshed, err := r.Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("Error reading the Container Registry: %+v", err)
+	}
+
+	if actual := refreshed.Attributes["admin_enabled"]; actual != "false" {
+		t.Fatalf("Expected `admin_enabled` to be false but got %q", actual)
+	}
+	for _, key := range []string{"admin_username", "admin_password", "admin_password2"} {
+		if actual := refreshed.Attributes[key]; actual != "" {
+			t.Fatalf("Expected %q to be cleared but got %q", key, actual)
+		}
+	}
+	for _, request := range sender.Requests {
+		if strings.HasSuffix(request.Path, "/listCredentials") {
+			t.Fatalf("Expected the Credentials not to be listed when the admin user is disabled")
+		}
+	}
+}
+
+func TestAzureRMContainerRegistry_changingDefaultTagsUpdatesExistingRegistry(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.ContainerRegistry/registries/acctestreg"
+
+	// the Registry was created when the `cost-center` default was "1234", and before the `team` default was added
+	body := fmt.Sprintf(`{"id": %q, "name": "acctestreg", "location": "westeurope", "sku": {"name": "Basic", "tier": "Basic"}, "properties": {"adminUserEnabled": false, "loginServer": "acctestreg.azurecr.io"}, "tags": {"environment": "production", "cost-center": "1234"}}`, id)
+	sender := testAzureResponseSender(http.StatusOK, body)
+	meta := testContainerRegistryMeta(sender)
+	meta.defaultTags = map[string]interface{}{
+		"cost-center": "5678",
+		"team":        "core",
+	}
+
+	state := &terraform.InstanceState{
+		ID: id,
+		Attributes: map[string]string{
+			"id":                         id,
+			"name":                       "acctestreg",
+			"resource_group_name":        "acctestRG",
+			"location":                   "westeurope",
+			"sku":                        "Basic",
+			"admin_enabled":              "false",
+			"georeplication_locations.#": "0",
+			"tags.%":                     "1",
+			"tags.environment":           "production",
+		},
+		Meta: map[string]interface{}{
+			"schema_version": "2",
+		},
+	}
+
+	r := resourceArmContainerRegistry()
+	refreshed, err := r.Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("Error reading the Container Registry: %+v", err)
+	}
+
+	rawConfig, err := config.NewRawConfig(map[string]interface{}{
+		"name":                "acctestreg",
+		"resource_group_name": "acctestRG",
+		"location":            "westeurope",
+		"sku":                 "Basic",
+		"tags": map[string]interface{}{
+			"environment": "production",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error building the configuration: %+v", err)
+	}
+
+	diff, err := r.Diff(refreshed, terraform.NewResourceConfig(rawConfig))
+	if err != nil {
+		t.Fatalf("Error diffing: %+v", err)
+	}
+	if diff.Empty() {
+		t.Fatalf("Expected the changed default tags to show as a diff")
+	}
+
+	sender.Requests = make([]testAzureRequest, 0)
+	if _, err := r.Apply(refreshed, diff, meta); err != nil {
+		t.Fatalf("Error applying: %+v", err)
+	}
+
+	expected := map[string]string{
+		"environment": "production",
+		"cost-center": "5678",
+		"team":        "core",
+	}
+	patched := false
+	for _, request := range sender.Requests {
+		if request.Method != http.MethodPatch {
+			continue
+		}
+		patched = true
+
+		var parameters struct {
+			Tags map[string]string `json:"tags"`
+		}
+		if err := json.Unmarshal([]byte(request.Body), &parameters); err != nil {
+			t.Fatalf("Error decoding the request: %+v", err)
+		}
+		if !reflect.DeepEqual(parameters.Tags, expected) {
+			t.Fatalf("Expected the tags %+v to be sent but got %+v", expected, parameters.Tags)
+		}
+	}
+	if !patched {
+		t.Fatalf("Expected the Container Registry to be updated but got the requests %+v", sender.Requests)
+	}
+}
+
+// testContainerRegistryMeta returns an ArmClient whose Container Registries client sends its requests to the sender
+func testContainerRegistryMeta(sender autorest.Sender) *ArmClient {
+	client := containerregistry.NewRegistriesClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	client.Sender = sender
+
+	return &ArmClient{
+		StopContext:             context.Background(),
+		containerRegistryClient: client,
+	}
+}
+
+// testContainerRegistryJSON returns a Container Registry as returned by the API, with the admin user disabled
+// and using the specified Storage Account when it's not empty
+func testContainerRegistryJSON(id string, sku string, storageAccountId string) string {
+	storageAccount := ""
+	if storageAccountId != "" {
+		storageAccount = fmt.Sprintf(`, "storageAccount": {"id": %q}`, storageAccountId)
+	}
+
+	return fmt.Sprintf(`{"id": %q, "name": "acctestreg", "location": "westeurope", "sku": {"name": %q, "tier": %q}, "properties": {"adminUserEnabled": false, "loginServer": "acctestreg.azurecr.io"%s}, "tags": {}}`, id, sku, sku, storageAccount)
+}
+
+func TestAzureRMContainerRegistry_checkNameAvailability(t *testing.T) {
+	cases := []struct {
+		StatusCode  int
+		Body        string
+		ExpectError bool
+	}{
+		{
+			StatusCode: http.StatusOK,
+			Body:       `{"nameAvailable": true}`,
+		},
+		{
+			StatusCode:  http.StatusOK,
+			Body:        `{"nameAvailable": false, "reason": "AlreadyExists", "message": "The registry acctestreg is already in use."}`,
+			ExpectError: true,
+		},
+		{
+			// the check isn't fatal, since the Create will fail anyway if the name's unavailable
+			StatusCode: http.StatusForbidden,
+			Body:       `{"error": {"code": "AuthorizationFailed", "message": "The client does not have authorization to perform action 'Microsoft.ContainerRegistry/checkNameAvailability/read'."}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		client := containerregistry.NewRegistriesClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+		client.Sender = testAzureResponseSender(tc.StatusCode, tc.Body)
+
+		err := checkContainerRegistryNameAvailability(client, "acctestreg")
+		if tc.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected an error for %s but didn't get one", tc.Body)
+			}
+
+			if !strings.Contains(err.Error(), "AlreadyExists") || !strings.Contains(err.Error(), "is already in use") {
+				t.Fatalf("Expected the error to include the reason given by Azure but got: %+v", err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected no error for %s but got: %+v", tc.Body, err)
+		}
+	}
+}
+
+func TestAzureRMContainerRegistry_listCredentials(t *testing.T) {
+	cases := []struct {
+		StatusCode  int
+		Body        string
+		ExpectCreds bool
+		ExpectError bool
+	}{
+		{
+			StatusCode:  http.StatusOK,
+			Body:        `{"username": "acctestreg", "passwords": [{"name": "password", "value": "s3cr3t"}]}`,
+			ExpectCreds: true,
+		},
+		{
+			// the principal can manage the Registry but isn't allowed to list its Credentials
+			StatusCode: http.StatusForbidden,
+			Body:       `{"error": {"code": "AuthorizationFailed", "message": "The client does not have authorization to perform action 'Microsoft.ContainerRegistry/registries/listCredentials/action'."}}`,
+		},
+		{
+			StatusCode:  http.StatusBadRequest,
+			Body:        `{"error": {"code": "BadRequest", "message": "Bad Request"}}`,
+			ExpectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		client := containerregistry.NewRegistriesClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+		client.Sender = testAzureResponseSender(tc.StatusCode, tc.Body)
+
+		creds, err := listContainerRegistryCredentials(context.Background(), client, "acctestrg", "acctestreg")
+		if tc.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected an error for Status Code %d but didn't get one", tc.StatusCode)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected no error for Status Code %d but got: %+v", tc.StatusCode, err)
+		}
+
+		if tc.ExpectCreds && (creds == nil || creds.Username == nil || *creds.Username != "acctestreg") {
+			t.Fatalf("Expected the Credentials for Status Code %d but got %+v", tc.StatusCode, creds)
+		}
+
+		if !tc.ExpectCreds && creds != nil {
+			t.Fatalf("Expected no Credentials for Status Code %d but got %+v", tc.StatusCode, creds)
+		}
 	}
 }
 
@@ -100,6 +639,8 @@ func TestAccAzureRMContainerRegistry_basicBasic(t *testing.T) {
 				Config: config,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckAzureRMContainerRegistryExists("azurerm_container_registry.test"),
+					resource.TestCheckResourceAttr("azurerm_container_registry.test", "admin_enabled", "false"),
+					testCheckAzureRMContainerRegistryLoginServerMatchesApi("azurerm_container_registry.test"),
 				),
 			},
 		},
@@ -138,6 +679,7 @@ func TestAccAzureRMContainerRegistry_basicPremium(t *testing.T) {
 				Config: config,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckAzureRMContainerRegistryExists("azurerm_container_registry.test"),
+					testCheckAzureRMContainerRegistryIdMatchesApi("azurerm_container_registry.test"),
 				),
 			},
 		},
@@ -180,12 +722,50 @@ func TestAccAzureRMContainerRegistry_update(t *testing.T) {
 				Config: config,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckAzureRMContainerRegistryExists("azurerm_container_registry.test"),
+					resource.TestCheckResourceAttr("azurerm_container_registry.test", "admin_username", ""),
+					resource.TestCheckResourceAttr("azurerm_container_registry.test", "admin_password", ""),
+					resource.TestCheckResourceAttr("azurerm_container_registry.test", "admin_password2", ""),
 				),
 			},
 			{
 				Config: updatedConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckAzureRMContainerRegistryExists("azurerm_container_registry.test"),
+					resource.TestCheckResourceAttrSet("azurerm_container_registry.test", "admin_username"),
+					resource.TestCheckResourceAttrSet("azurerm_container_registry.test", "admin_password"),
+					resource.TestCheckResourceAttrSet("azurerm_container_registry.test", "admin_password2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMContainerRegistry_geoReplication(t *testing.T) {
+	resourceName := "azurerm_container_registry.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+	altLocation := testAltLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMContainerRegistryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMContainerRegistry_geoReplication(ri, location, altLocation),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMContainerRegistryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "georeplication_locations.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "replications.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "replications.0.status", "Ready"),
+					resource.TestCheckResourceAttr(resourceName, "replications.1.status", "Ready"),
+				),
+			},
+			{
+				Config: testAccAzureRMContainerRegistry_basicManaged(ri, location, "Premium"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMContainerRegistryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "georeplication_locations.#", "0"),
 				),
 			},
 		},
@@ -245,6 +825,64 @@ func testCheckAzureRMContainerRegistryExists(name string) resource.TestCheckFunc
 	}
 }
 
+func testCheckAzureRMContainerRegistryIdMatchesApi(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		registryName := rs.Primary.Attributes["name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+
+		conn := testAccProvider.Meta().(*ArmClient).containerRegistryClient
+
+		resp, err := conn.Get(resourceGroup, registryName)
+		if err != nil {
+			return fmt.Errorf("Bad: Get on containerRegistryClient: %+v", err)
+		}
+
+		if resp.ID == nil {
+			return fmt.Errorf("Bad: Container Registry %q (resource group: %q) has no ID", registryName, resourceGroup)
+		}
+
+		if rs.Primary.ID != *resp.ID {
+			return fmt.Errorf("Bad: expected the ID in state to be %q but got %q", *resp.ID, rs.Primary.ID)
+		}
+
+		return nil
+	}
+}
+
+func testCheckAzureRMContainerRegistryLoginServerMatchesApi(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		registryName := rs.Primary.Attributes["name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+
+		conn := testAccProvider.Meta().(*ArmClient).containerRegistryClient
+
+		resp, err := conn.Get(resourceGroup, registryName)
+		if err != nil {
+			return fmt.Errorf("Bad: Get on containerRegistryClient: %+v", err)
+		}
+
+		if resp.RegistryProperties == nil || resp.LoginServer == nil {
+			return fmt.Errorf("Bad: Container Registry %q (resource group: %q) has no Login Server", registryName, resourceGroup)
+		}
+
+		if actual := rs.Primary.Attributes["login_server"]; actual != *resp.LoginServer {
+			return fmt.Errorf("Bad: expected the Login Server in state to be %q but got %q", *resp.LoginServer, actual)
+		}
+
+		return nil
+	}
+}
+
 func testAccAzureRMContainerRegistry_basicManaged(rInt int, location string, sku string) string {
 	return fmt.Sprintf(`
 resource "azurerm_resource_group" "test" {
@@ -345,3 +983,20 @@ resource "azurerm_container_registry" "test" {
 }
 `, rInt, location, rStr, rInt)
 }
+
+func testAccAzureRMContainerRegistry_geoReplication(rInt int, location string, altLocation string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "testAccRg-%d"
+  location = "%s"
+}
+
+resource "azurerm_container_registry" "test" {
+  name                     = "testacccr%d"
+  resource_group_name      = "${azurerm_resource_group.test.name}"
+  location                 = "${azurerm_resource_group.test.location}"
+  sku                      = "Premium"
+  georeplication_locations = ["%s"]
+}
+`, rInt, location, rInt, altLocation)
+}
@@ -0,0 +1,297 @@
+package azurerm
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"github.com/Azure/azure-sdk-for-go/arm/containerregistry"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/response"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func resourceArmContainerRegistryWebhook() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceArmContainerRegistryWebhookCreate,
+		Read:   resourceArmContainerRegistryWebhookRead,
+		Update: resourceArmContainerRegistryWebhookUpdate,
+		Delete: resourceArmContainerRegistryWebhookDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateAzureRMContainerRegistryWebhookName,
+			},
+
+			"resource_group_name": resourceGroupNameSchema(),
+
+			"registry_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"location": locationSchema(),
+
+			"service_uri": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateAbsoluteURL,
+			},
+
+			"actions": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{
+						string(containerregistry.Push),
+						string(containerregistry.Delete),
+					}, false),
+				},
+				Set: schema.HashString,
+			},
+
+			"custom_headers": {
+				Type:      schema.TypeMap,
+				Optional:  true,
+				Sensitive: true,
+			},
+
+			"scope": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  string(containerregistry.Enabled),
+				ValidateFunc: validation.StringInSlice([]string{
+					string(containerregistry.Enabled),
+					string(containerregistry.Disabled),
+				}, false),
+			},
+
+			"tags": tagsSchema(),
+		},
+	}
+}
+
+func resourceArmContainerRegistryWebhookCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).containerRegistryWebhooksClient
+	log.Printf("[INFO] preparing arguments for AzureRM Container Registry Webhook creation.")
+
+	name := d.Get("name").(string)
+	resourceGroup := d.Get("resource_group_name").(string)
+	registryName := d.Get("registry_name").(string)
+	location := d.Get("location").(string)
+	tags := d.Get("tags").(map[string]interface{})
+
+	parameters := containerregistry.WebhookCreateParameters{
+		Location: utils.String(location),
+		WebhookPropertiesCreateParameters: &containerregistry.WebhookPropertiesCreateParameters{
+			ServiceURI:    utils.String(d.Get("service_uri").(string)),
+			CustomHeaders: expandContainerRegistryWebhookCustomHeaders(d),
+			Status:        containerregistry.WebhookStatus(d.Get("status").(string)),
+			Scope:         utils.String(d.Get("scope").(string)),
+			Actions:       expandContainerRegistryWebhookActions(d),
+		},
+		Tags: expandTags(tags),
+	}
+
+	_, createErr := client.Create(resourceGroup, registryName, name, parameters, make(chan struct{}))
+	if err := <-createErr; err != nil {
+		return fmt.Errorf("Error creating Container Registry Webhook %q (Registry %q / Resource Group %q): %+v", name, registryName, resourceGroup, err)
+	}
+
+	read, err := client.Get(resourceGroup, registryName, name)
+	if err != nil {
+		return fmt.Errorf("Error retrieving Container Registry Webhook %q (Registry %q / Resource Group %q): %+v", name, registryName, resourceGroup, err)
+	}
+
+	if read.ID == nil {
+		return fmt.Errorf("Cannot read Container Registry Webhook %q (Registry %q / Resource Group %q) ID", name, registryName, resourceGroup)
+	}
+
+	d.SetId(*read.ID)
+
+	return resourceArmContainerRegistryWebhookRead(d, meta)
+}
+
+func resourceArmContainerRegistryWebhookUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).containerRegistryWebhooksClient
+	log.Printf("[INFO] preparing arguments for AzureRM Container Registry Webhook update.")
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resourceGroup := id.ResourceGroup
+	registryName := id.Path["registries"]
+	name := id.Path["webhooks"]
+	tags := d.Get("tags").(map[string]interface{})
+
+	parameters := containerregistry.WebhookUpdateParameters{
+		WebhookPropertiesUpdateParameters: &containerregistry.WebhookPropertiesUpdateParameters{
+			ServiceURI:    utils.String(d.Get("service_uri").(string)),
+			CustomHeaders: expandContainerRegistryWebhookCustomHeaders(d),
+			Status:        containerregistry.WebhookStatus(d.Get("status").(string)),
+			Scope:         utils.String(d.Get("scope").(string)),
+			Actions:       expandContainerRegistryWebhookActions(d),
+		},
+		Tags: expandTags(tags),
+	}
+
+	_, updateErr := client.Update(resourceGroup, registryName, name, parameters, make(chan struct{}))
+	if err := <-updateErr; err != nil {
+		return fmt.Errorf("Error updating Container Registry Webhook %q (Registry %q / Resource Group %q): %+v", name, registryName, resourceGroup, err)
+	}
+
+	return resourceArmContainerRegistryWebhookRead(d, meta)
+}
+
+func resourceArmContainerRegistryWebhookRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).containerRegistryWebhooksClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resourceGroup := id.ResourceGroup
+	registryName := id.Path["registries"]
+	name := id.Path["webhooks"]
+
+	resp, err := client.Get(resourceGroup, registryName, name)
+	if err != nil {
+		if utils.ResponseWasNotFound(resp.Response) {
+			d.SetId("")
+			return nil
+		}
+
+		return fmt.Errorf("Error making Read request on Azure Container Registry Webhook %q (Registry %q / Resource Group %q): %+v", name, registryName, resourceGroup, err)
+	}
+
+	// the Service URI and Custom Headers are only available via the Callback Config - which needs
+	// more permissions than reading the Webhook, in which case the values in the state are kept
+	callbackConfig, err := client.GetCallbackConfig(resourceGroup, registryName, name)
+	callbackConfigForbidden := response.WasForbidden(callbackConfig.Response.Response)
+	if err != nil {
+		if !callbackConfigForbidden {
+			return fmt.Errorf("Error retrieving the Callback Config for Container Registry Webhook %q (Registry %q / Resource Group %q): %+v", name, registryName, resourceGroup, err)
+		}
+
+		log.Printf("[DEBUG] Not permitted to retrieve the Callback Config for Container Registry Webhook %q (Registry %q / Resource Group %q) - keeping the `service_uri` and `custom_headers` in the state: %+v", name, registryName, resourceGroup, err)
+	}
+
+	d.Set("name", resp.Name)
+	d.Set("resource_group_name", resourceGroup)
+	d.Set("registry_name", registryName)
+	if location := resp.Location; location != nil {
+		d.Set("location", azureRMNormalizeLocation(*location))
+	}
+
+	if !callbackConfigForbidden {
+		d.Set("service_uri", callbackConfig.ServiceURI)
+		d.Set("custom_headers", flattenContainerRegistryWebhookCustomHeaders(callbackConfig.CustomHeaders))
+	}
+
+	if props := resp.WebhookProperties; props != nil {
+		d.Set("status", string(props.Status))
+		d.Set("scope", props.Scope)
+		d.Set("actions", flattenContainerRegistryWebhookActions(props.Actions))
+	}
+
+	flattenAndSetTags(d, resp.Tags)
+
+	return nil
+}
+
+func resourceArmContainerRegistryWebhookDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).containerRegistryWebhooksClient
+
+	id, err := parseAzureResourceID(d.Id())
+	if err != nil {
+		return err
+	}
+	resourceGroup := id.ResourceGroup
+	registryName := id.Path["registries"]
+	name := id.Path["webhooks"]
+
+	deleteResp, deleteErr := client.Delete(resourceGroup, registryName, name, make(chan struct{}))
+	resp := <-deleteResp
+	err = <-deleteErr
+
+	if err != nil {
+		if !utils.ResponseWasNotFound(resp) {
+			return fmt.Errorf("Error issuing Azure ARM delete request of Container Registry Webhook %q (Registry %q / Resource Group %q): %+v", name, registryName, resourceGroup, err)
+		}
+	}
+
+	return nil
+}
+
+func expandContainerRegistryWebhookActions(d *schema.ResourceData) *[]containerregistry.WebhookAction {
+	actions := make([]containerregistry.WebhookAction, 0)
+	for _, v := range d.Get("actions").(*schema.Set).List() {
+		actions = append(actions, containerregistry.WebhookAction(v.(string)))
+	}
+	return &actions
+}
+
+func flattenContainerRegistryWebhookActions(input *[]containerregistry.WebhookAction) *schema.Set {
+	actions := &schema.Set{F: schema.HashString}
+	if input != nil {
+		for _, action := range *input {
+			actions.Add(string(action))
+		}
+	}
+	return actions
+}
+
+func expandContainerRegistryWebhookCustomHeaders(d *schema.ResourceData) *map[string]*string {
+	headers := make(map[string]*string)
+	for k, v := range d.Get("custom_headers").(map[string]interface{}) {
+		value := v.(string)
+		headers[k] = &value
+	}
+	return &headers
+}
+
+func flattenContainerRegistryWebhookCustomHeaders(input *map[string]*string) map[string]interface{} {
+	headers := make(map[string]interface{})
+	if input != nil {
+		for k, v := range *input {
+			if v != nil {
+				headers[k] = *v
+			}
+		}
+	}
+	return headers
+}
+
+func validateAzureRMContainerRegistryWebhookName(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !regexp.MustCompile(`^[a-zA-Z0-9]+$`).MatchString(value) {
+		errors = append(errors, fmt.Errorf(
+			"alpha numeric characters only are allowed in %q: %q", k, value))
+	}
+
+	if 5 > len(value) {
+		errors = append(errors, fmt.Errorf("%q cannot be less than 5 characters: %q", k, value))
+	}
+
+	if len(value) > 50 {
+		errors = append(errors, fmt.Errorf("%q cannot be longer than 50 characters: %q %d", k, value, len(value)))
+	}
+
+	return
+}
@@ -0,0 +1,268 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/arm/containerregistry"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/hashicorp/terraform/helper/acctest"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
+)
+
+func TestAccAzureRMContainerRegistryWebhookName_validation(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{
+			Value:    "four",
+			ErrCount: 1,
+		},
+		{
+			Value:    "5five",
+			ErrCount: 0,
+		},
+		{
+			Value:    "hello-world",
+			ErrCount: 1,
+		},
+		{
+			Value:    "webhook1",
+			ErrCount: 0,
+		},
+		{
+			Value:    "qfvbdsbvipqdbwsbddbdcwqffewsqwcdw21ddwqwd33241202",
+			ErrCount: 0,
+		},
+		{
+			Value:    "qfvbdsbvipqdbwsbddbdcwqfjjfewsqwcdw21ddwqwd33241202",
+			ErrCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateAzureRMContainerRegistryWebhookName(tc.Value, "azurerm_container_registry_webhook")
+
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected the Azure RM Container Registry Webhook Name %q to trigger %d validation errors but got %d", tc.Value, tc.ErrCount, len(errors))
+		}
+	}
+}
+
+func TestAccAzureRMContainerRegistryWebhook_basic(t *testing.T) {
+	resourceName := "azurerm_container_registry_webhook.test"
+	ri := acctest.RandInt()
+	config := testAccAzureRMContainerRegistryWebhook_basic(ri, testLocation())
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMContainerRegistryWebhookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMContainerRegistryWebhookExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "status", "enabled"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMContainerRegistryWebhook_update(t *testing.T) {
+	resourceName := "azurerm_container_registry_webhook.test"
+	ri := acctest.RandInt()
+	location := testLocation()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMContainerRegistryWebhookDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMContainerRegistryWebhook_basic(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMContainerRegistryWebhookExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "status", "enabled"),
+				),
+			},
+			{
+				Config: testAccAzureRMContainerRegistryWebhook_complete(ri, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMContainerRegistryWebhookExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "actions.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "status", "disabled"),
+					resource.TestCheckResourceAttr(resourceName, "scope", "mytag:*"),
+					resource.TestCheckResourceAttr(resourceName, "custom_headers.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAzureRMContainerRegistryWebhook_readKeepsCallbackConfigWhenForbidden(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.ContainerRegistry/registries/acctestcr/webhooks/acctestwh"
+
+	client := containerregistry.NewWebhooksClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	client.Sender = autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(r.URL.Path, "/getCallbackConfig") {
+			return testAzureResponse(r, http.StatusForbidden, `{"error":{"code":"AuthorizationFailed","message":"The client does not have authorization to perform action."}}`), nil
+		}
+
+		body := fmt.Sprintf(`{"id": %q, "name": "acctestwh", "location": "westeurope", "properties": {"status": "enabled", "scope": "", "actions": ["push"]}}`, id)
+		return testAzureResponse(r, http.StatusOK, body), nil
+	})
+	meta := &ArmClient{
+		containerRegistryWebhooksClient: client,
+	}
+
+	state := &terraform.InstanceState{
+		ID: id,
+		Attributes: map[string]string{
+			"service_uri":                 "https://mywebhookreceiver.example/mytag",
+			"custom_headers.%":            "1",
+			"custom_headers.Content-Type": "application/json",
+		},
+	}
+
+	state, err := resourceArmContainerRegistryWebhook().Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("Expected the Webhook to be read without its Callback Config but got: %+v", err)
+	}
+
+	if actual := state.Attributes["service_uri"]; actual != "https://mywebhookreceiver.example/mytag" {
+		t.Fatalf("Expected the `service_uri` to be kept but got %q", actual)
+	}
+
+	if actual := state.Attributes["custom_headers.Content-Type"]; actual != "application/json" {
+		t.Fatalf("Expected the `custom_headers` to be kept but got %+v", state.Attributes)
+	}
+
+	if actual := state.Attributes["status"]; actual != "enabled" {
+		t.Fatalf("Expected the `status` to be read from the Webhook but got %q", actual)
+	}
+}
+
+func testCheckAzureRMContainerRegistryWebhookDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*ArmClient).containerRegistryWebhooksClient
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "azurerm_container_registry_webhook" {
+			continue
+		}
+
+		name := rs.Primary.Attributes["name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+		registryName := rs.Primary.Attributes["registry_name"]
+
+		resp, err := client.Get(resourceGroup, registryName, name)
+		if err != nil {
+			if !utils.ResponseWasNotFound(resp.Response) {
+				return err
+			}
+			return nil
+		}
+
+		return fmt.Errorf("Container Registry Webhook %q still exists", name)
+	}
+
+	return nil
+}
+
+func testCheckAzureRMContainerRegistryWebhookExists(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		// Ensure we have enough information in state to look up in API
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %s", name)
+		}
+
+		webhookName := rs.Primary.Attributes["name"]
+		registryName := rs.Primary.Attributes["registry_name"]
+		resourceGroup, hasResourceGroup := rs.Primary.Attributes["resource_group_name"]
+		if !hasResourceGroup {
+			return fmt.Errorf("Bad: no resource group found in state for Container Registry Webhook: %s", webhookName)
+		}
+
+		client := testAccProvider.Meta().(*ArmClient).containerRegistryWebhooksClient
+
+		resp, err := client.Get(resourceGroup, registryName, webhookName)
+		if err != nil {
+			if utils.ResponseWasNotFound(resp.Response) {
+				return fmt.Errorf("Bad: Container Registry Webhook %q (Registry %q / Resource Group: %q) does not exist", webhookName, registryName, resourceGroup)
+			}
+
+			return fmt.Errorf("Bad: Get on containerRegistryWebhooksClient: %+v", err)
+		}
+
+		return nil
+	}
+}
+
+func testAccAzureRMContainerRegistryWebhook_basic(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "testAccRg-%d"
+  location = "%s"
+}
+
+resource "azurerm_container_registry" "test" {
+  name                = "testacccr%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  location            = "${azurerm_resource_group.test.location}"
+  sku                 = "Standard"
+}
+
+resource "azurerm_container_registry_webhook" "test" {
+  name                = "testaccwebhook%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  registry_name       = "${azurerm_container_registry.test.name}"
+  location            = "${azurerm_resource_group.test.location}"
+  service_uri         = "https://mywebhookreceiver.example/mytag"
+  actions             = ["push"]
+}
+`, rInt, location, rInt, rInt)
+}
+
+func testAccAzureRMContainerRegistryWebhook_complete(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "testAccRg-%d"
+  location = "%s"
+}
+
+resource "azurerm_container_registry" "test" {
+  name                = "testacccr%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  location            = "${azurerm_resource_group.test.location}"
+  sku                 = "Standard"
+}
+
+resource "azurerm_container_registry_webhook" "test" {
+  name                = "testaccwebhook%d"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  registry_name       = "${azurerm_container_registry.test.name}"
+  location            = "${azurerm_resource_group.test.location}"
+  service_uri         = "https://mywebhookreceiver.example/mytag"
+  actions             = ["push", "delete"]
+  status              = "disabled"
+  scope               = "mytag:*"
+
+  custom_headers {
+    "Content-Type" = "application/json"
+  }
+
+  tags {
+    environment = "production"
+  }
+}
+`, rInt, location, rInt, rInt)
+}
@@ -1,44 +1,102 @@
 package azurerm
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/arm/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/response"
 	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
 )
 
 var networkSecurityGroupResourceName = "azurerm_network_security_group"
 
+// openSecurityRuleWarningsEnvVar enables warnings about Security Rules which allow inbound traffic from the Internet
+// to any port, and is the default for the provider's `warn_on_open_security_rules`
+const openSecurityRuleWarningsEnvVar = "ARM_WARN_ON_OPEN_SECURITY_RULES"
+
+func openSecurityRuleWarningsEnabledFromEnvironment() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(openSecurityRuleWarningsEnvVar))
+	return enabled
+}
+
+// networkSecurityGroupInUseErrorCode is the error code Azure returns when deleting a Network Security Group
+// which is still associated with a Subnet or Network Interface
+const networkSecurityGroupInUseErrorCode = "InUseNetworkSecurityGroup"
+
+// networkSecurityRuleDescriptionMaxLength is the longest description Azure accepts for a Security Rule
+const networkSecurityRuleDescriptionMaxLength = 140
+
+// networkSecurityRuleMinPriority and networkSecurityRuleMaxPriority are the range of priorities Azure accepts for a Security Rule.
+// The default rules use priorities from 65000 upwards, so a custom rule can't collide with them.
+const (
+	networkSecurityRuleMinPriority = 100
+	networkSecurityRuleMaxPriority = 4096
+)
+
+// networkSecurityRulePrioritySpacing is the gap below which priorities in the same direction are considered
+// too close together to leave room for inserting rules between them later
+const networkSecurityRulePrioritySpacing = 100
+
+// networkSecurityGroupMaxSecurityRules is the number of Security Rules Azure allows in a Network Security Group -
+// this is checked at plan time, so should be updated if Azure raises the limit
+const networkSecurityGroupMaxSecurityRules = 1000
+
 func resourceArmNetworkSecurityGroup() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceArmNetworkSecurityGroupCreate,
 		Read:   resourceArmNetworkSecurityGroupRead,
-		Update: resourceArmNetworkSecurityGroupCreate,
+		Update: resourceArmNetworkSecurityGroupUpdate,
 		Delete: resourceArmNetworkSecurityGroupDelete,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		MigrateState:  resourceAzureRMNetworkSecurityGroupMigrateState,
+		SchemaVersion: 1,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Read:   schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
+				// resource names are case-insensitive in Azure, so changing only the casing shouldn't recreate the NSG
+				DiffSuppressFunc: ignoreCaseDiffSuppressFunc,
 			},
 
 			"location": locationSchema(),
 
 			"resource_group_name": resourceGroupNameSchema(),
 
+			// this is Computed so that omitting it leaves the existing rules in place (e.g. when they're managed by
+			// `azurerm_network_security_rule` resources) - removing all of the rules requires `security_rule = []`
 			"security_rule": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Computed: true,
+				Type:          schema.TypeSet,
+				Optional:      true,
+				Computed:      true,
+				MaxItems:      networkSecurityGroupMaxSecurityRules,
+				Set:           resourceArmNetworkSecurityGroupRuleHash,
+				ConflictsWith: []string{"security_rules_json"},
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"name": {
@@ -49,7 +107,7 @@ func resourceArmNetworkSecurityGroup() *schema.Resource {
 						"description": {
 							Type:         schema.TypeString,
 							Optional:     true,
-							ValidateFunc: validateStringLength(140),
+							ValidateFunc: validateStringLength(networkSecurityRuleDescriptionMaxLength),
 						},
 
 						"protocol": {
@@ -60,23 +118,86 @@ func resourceArmNetworkSecurityGroup() *schema.Resource {
 						},
 
 						"source_port_range": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validatePortRangeListOrStar,
+						},
+
+						"source_port_ranges": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validatePortRangeOrStar,
+							},
+							Set: schema.HashString,
 						},
 
 						"destination_port_range": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validatePortRangeListOrStar,
+						},
+
+						"destination_port_ranges": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validatePortRangeOrStar,
+							},
+							Set: schema.HashString,
 						},
 
 						"source_address_prefix": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateNetworkSecurityRuleAddressPrefix,
+						},
+
+						"source_address_prefix_is_service_tag": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+
+						"source_address_prefixes": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateNetworkSecurityRuleAddressPrefix,
+							},
+							Set: schema.HashString,
 						},
 
 						"destination_address_prefix": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validateNetworkSecurityRuleAddressPrefix,
+						},
+
+						"destination_address_prefixes": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateNetworkSecurityRuleAddressPrefix,
+							},
+							Set: schema.HashString,
+						},
+
+						"source_application_security_group_ids": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
+						},
+
+						"destination_application_security_group_ids": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Set:      schema.HashString,
 						},
 
 						"access": {
@@ -92,7 +213,7 @@ func resourceArmNetworkSecurityGroup() *schema.Resource {
 						"priority": {
 							Type:         schema.TypeInt,
 							Required:     true,
-							ValidateFunc: validation.IntBetween(100, 4096),
+							ValidateFunc: validation.IntBetween(networkSecurityRuleMinPriority, networkSecurityRuleMaxPriority),
 						},
 
 						"direction": {
@@ -108,6 +229,63 @@ func resourceArmNetworkSecurityGroup() *schema.Resource {
 				},
 			},
 
+			"default_security_rule": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     computedNetworkSecurityRuleSchema(),
+			},
+
+			"security_rule_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"highest_priority": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+
+			"security_rule_names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			// opt-in, since retrieving the Flow Log status lists the Network Watchers in the subscription
+			// and requires permissions on them, which managing the Network Security Group doesn't
+			"retrieve_network_watcher_flow_log_status": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"network_watcher_flow_log_enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+
+			"warn_on_security_rule_priority_spacing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			// opt-in, since setting it changes the description of any existing rules which don't specify one
+			"security_rule_default_description": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(1, networkSecurityRuleDescriptionMaxLength),
+			},
+
+			// for rule sets generated outside of HCL - `security_rule` is still populated from Azure when this is set
+			"security_rules_json": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateNetworkSecurityRulesJSON,
+				StateFunc:     normalizeJson,
+				ConflictsWith: []string{"security_rule"},
+			},
+
 			"tags": tagsSchema(),
 		},
 	}
@@ -116,12 +294,15 @@ func resourceArmNetworkSecurityGroup() *schema.Resource {
 func resourceArmNetworkSecurityGroupCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*ArmClient).secGroupClient
 
+	timeout := d.Timeout(schema.TimeoutCreate)
+	client.PollingDuration = timeout
+
 	name := d.Get("name").(string)
 	location := d.Get("location").(string)
 	resGroup := d.Get("resource_group_name").(string)
-	tags := d.Get("tags").(map[string]interface{})
+	tags := mergeDefaultTags(meta.(*ArmClient).defaultTags, d.Get("tags").(map[string]interface{}))
 
-	sgRules, sgErr := expandAzureRmSecurityRules(d)
+	sgRules, sgErr := expandAzureRmSecurityRules(d, meta.(*ArmClient).warnOnOpenSecurityRules)
 	if sgErr != nil {
 		return fmt.Errorf("Error Building list of Network Security Group Rules: %+v", sgErr)
 	}
@@ -138,39 +319,85 @@ func resourceArmNetworkSecurityGroupCreate(d *schema.ResourceData, meta interfac
 		Tags: expandTags(tags),
 	}
 
-	_, createErr := client.CreateOrUpdate(resGroup, name, sg, make(chan struct{}))
-	err := <-createErr
-	if err != nil {
+	if err := createOrUpdateNetworkSecurityGroup(client, resGroup, name, sg, timeout); err != nil {
 		return err
 	}
 
-	read, err := client.Get(resGroup, name, "")
+	read, err := waitForNetworkSecurityGroupToBeAvailable(client, resGroup, name, timeout)
 	if err != nil {
 		return err
 	}
 	if read.ID == nil {
-		return fmt.Errorf("Cannot read Virtual Network %q (resource group %q) ID", name, resGroup)
+		return fmt.Errorf("Cannot read Network Security Group %q (resource group %q) ID", name, resGroup)
 	}
 
-	log.Printf("[DEBUG] Waiting for NSG (%q) to become available", name)
-	stateConf := &resource.StateChangeConf{
-		Pending:    []string{"Updating", "Creating"},
-		Target:     []string{"Succeeded"},
-		Refresh:    networkSecurityGroupStateRefreshFunc(client, resGroup, name),
-		Timeout:    30 * time.Minute,
-		MinTimeout: 15 * time.Second,
+	d.SetId(*read.ID)
+
+	return resourceArmNetworkSecurityGroupRead(d, meta)
+}
+
+func resourceArmNetworkSecurityGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ArmClient).secGroupClient
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	client.PollingDuration = timeout
+
+	name := d.Get("name").(string)
+	resGroup := d.Get("resource_group_name").(string)
+
+	azureRMLockByName(name, networkSecurityGroupResourceName)
+	defer azureRMUnlockByName(name, networkSecurityGroupResourceName)
+
+	// the API only supports a PUT of the whole Network Security Group, so we start from its current state
+	// and only rebuild the Security Rules when they've changed, rather than on every update
+	sg, err := client.Get(resGroup, name, "")
+	if err != nil {
+		return fmt.Errorf("Error retrieving Network Security Group %q (Resource Group %q): %+v", name, resGroup, err)
 	}
-	if _, err := stateConf.WaitForState(); err != nil {
-		return fmt.Errorf("Error waiting for NSG (%q) to become available: %+v", name, err)
+
+	if sg.SecurityGroupPropertiesFormat == nil {
+		sg.SecurityGroupPropertiesFormat = &network.SecurityGroupPropertiesFormat{}
 	}
 
-	d.SetId(*read.ID)
+	if d.HasChange("security_rule") || d.HasChange("security_rules_json") || d.HasChange("security_rule_default_description") {
+		old, new := d.GetChange("security_rule")
+		log.Printf("[DEBUG] Updating the Security Rules %s of Network Security Group %q (Resource Group %q)", strings.Join(changedNetworkSecurityRuleNames(old.(*schema.Set), new.(*schema.Set)), ", "), name, resGroup)
+
+		sgRules, sgErr := expandAzureRmSecurityRules(d, meta.(*ArmClient).warnOnOpenSecurityRules)
+		if sgErr != nil {
+			return fmt.Errorf("Error Building list of Network Security Group Rules: %+v", sgErr)
+		}
+
+		if len(sgRules) == 0 {
+			log.Printf("[WARN] Removing all of the Security Rules from Network Security Group %q (Resource Group %q) since `security_rule` is empty", name, resGroup)
+		}
+
+		sg.SecurityGroupPropertiesFormat.SecurityRules = &sgRules
+	}
+
+	// the tags are always sent (rather than only when `tags` changes) so that changes to the provider's `default_tags` are applied
+	tags := mergeDefaultTags(meta.(*ArmClient).defaultTags, d.Get("tags").(map[string]interface{}))
+	sg.Tags = expandTags(mergeHiddenTags(tags, sg.Tags, meta.(*ArmClient).hiddenTagPrefix))
+
+	// these are read-only, so there is no need to send them back to the API
+	sg.SecurityGroupPropertiesFormat.DefaultSecurityRules = nil
+	sg.SecurityGroupPropertiesFormat.NetworkInterfaces = nil
+	sg.SecurityGroupPropertiesFormat.Subnets = nil
+
+	if err := createOrUpdateNetworkSecurityGroup(client, resGroup, name, sg, timeout); err != nil {
+		return err
+	}
+
+	if _, err := waitForNetworkSecurityGroupToBeAvailable(client, resGroup, name, timeout); err != nil {
+		return err
+	}
 
 	return resourceArmNetworkSecurityGroupRead(d, meta)
 }
 
 func resourceArmNetworkSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*ArmClient).secGroupClient
+	client.Sender = autorest.DecorateSender(client.Sender, withRequestTimeout(d.Timeout(schema.TimeoutRead)))
 
 	id, err := parseAzureResourceID(d.Id())
 	if err != nil {
@@ -185,24 +412,68 @@ func resourceArmNetworkSecurityGroupRead(d *schema.ResourceData, meta interface{
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("Error making Read request on Azure Network Security Group %q: %+v", name, err)
+		return networkSecurityGroupRequestError(resp.Response.Response, err, "Error making Read request on Azure Network Security Group %q (Resource Group %q)", name, resGroup)
 	}
 
 	d.Set("name", resp.Name)
 	d.Set("resource_group_name", resGroup)
-	d.Set("location", azureRMNormalizeLocation(*resp.Location))
+	if location := resp.Location; location != nil {
+		d.Set("location", azureRMNormalizeLocation(*location))
+	}
 
+	securityRuleCount := 0
+	highestPriority := 0
+	securityRuleNames := make([]string, 0)
 	if props := resp.SecurityGroupPropertiesFormat; props != nil {
-		d.Set("security_rule", flattenNetworkSecurityRules(props.SecurityRules))
+		rules := flattenNetworkSecurityRules(props.SecurityRules, d.Get("security_rule_default_description").(string))
+		rules = restoreCommaSeparatedPortRanges(rules, d.Get("security_rule").(*schema.Set).List())
+		d.Set("security_rule", rules)
+
+		// the default rules are created by Azure and can't be modified, but are part of the effective rule set
+		if err := d.Set("default_security_rule", flattenNetworkSecurityRules(props.DefaultSecurityRules, "")); err != nil {
+			return fmt.Errorf("Error setting `default_security_rule`: %+v", err)
+		}
+
+		securityRuleCount = len(rules)
+		for _, raw := range rules {
+			rule := raw.(map[string]interface{})
+			if priority, ok := rule["priority"].(int); ok && priority > highestPriority {
+				highestPriority = priority
+			}
+			if ruleName, ok := rule["name"].(string); ok {
+				securityRuleNames = append(securityRuleNames, ruleName)
+			}
+
+			// rules created outside of Terraform can exceed the limit, which will fail the next time they're sent to Azure
+			if description, ok := rule["description"].(string); ok && len(description) > networkSecurityRuleDescriptionMaxLength {
+				log.Printf("[WARN] The description of Security Rule %q in Network Security Group %q (Resource Group %q) is longer than %d characters and will be rejected if it's updated by Terraform", rule["name"], name, resGroup, networkSecurityRuleDescriptionMaxLength)
+			}
+		}
+	}
+	d.Set("security_rule_count", securityRuleCount)
+	d.Set("highest_priority", highestPriority)
+
+	sort.Strings(securityRuleNames)
+	d.Set("security_rule_names", securityRuleNames)
+
+	flowLogEnabled := false
+	if d.Get("retrieve_network_watcher_flow_log_status").(bool) && resp.ID != nil && resp.Location != nil {
+		enabled, err := retrieveNetworkSecurityGroupFlowLogStatus(meta, *resp.ID, *resp.Location)
+		if err != nil {
+			return fmt.Errorf("Error retrieving the Flow Log status for Network Security Group %q (Resource Group %q): %+v", name, resGroup, err)
+		}
+		flowLogEnabled = enabled
 	}
+	d.Set("network_watcher_flow_log_enabled", flowLogEnabled)
 
-	flattenAndSetTags(d, resp.Tags)
+	flattenAndSetTagsWithDefaults(d, resp.Tags, meta.(*ArmClient).defaultTags, meta.(*ArmClient).hiddenTagPrefix)
 
 	return nil
 }
 
 func resourceArmNetworkSecurityGroupDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*ArmClient).secGroupClient
+	client.PollingDuration = d.Timeout(schema.TimeoutDelete)
 
 	id, err := parseAzureResourceID(d.Id())
 	if err != nil {
@@ -211,13 +482,200 @@ func resourceArmNetworkSecurityGroupDelete(d *schema.ResourceData, meta interfac
 	resGroup := id.ResourceGroup
 	name := id.Path["networkSecurityGroups"]
 
-	_, deleteErr := client.Delete(resGroup, name, make(chan struct{}))
-	err = <-deleteErr
+	deleteResp, deleteErr := client.Delete(resGroup, name, make(chan struct{}))
+	resp := <-deleteResp
+	if err := <-deleteErr; err != nil {
+		if wasNetworkSecurityGroupInUse(err) {
+			return networkSecurityGroupRequestError(resp.Response, err, "Error deleting Network Security Group %q (Resource Group %q): it's still associated with one or more Subnets or Network Interfaces, which must be disassociated from it first", name, resGroup)
+		}
+
+		return networkSecurityGroupRequestError(resp.Response, err, "Error deleting Network Security Group %q (Resource Group %q)", name, resGroup)
+	}
+
+	// the NSG can still exist for a while after the delete has completed (e.g. whilst it's being detached)
+	log.Printf("[DEBUG] Waiting for Network Security Group %q (Resource Group %q) to be deleted", name, resGroup)
+	stateConf := &resource.StateChangeConf{
+		Pending:    []string{"Exists"},
+		Target:     []string{"Deleted"},
+		Refresh:    networkSecurityGroupDeletedRefreshFunc(client, resGroup, name),
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		MinTimeout: 15 * time.Second,
+	}
+	if _, err := stateConf.WaitForState(); err != nil {
+		return fmt.Errorf("Error waiting for Network Security Group %q (Resource Group %q) to be deleted: %+v", name, resGroup, err)
+	}
+
+	return nil
+}
+
+func networkSecurityGroupDeletedRefreshFunc(client network.SecurityGroupsClient, resourceGroupName string, sgName string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		res, err := client.Get(resourceGroupName, sgName, "")
+		if err != nil {
+			if utils.ResponseWasNotFound(res.Response) {
+				// WaitForState treats a nil result as "not found" and keeps polling, so return the response instead
+				return res, "Deleted", nil
+			}
+
+			return nil, "", networkSecurityGroupRequestError(res.Response.Response, err, "Error retrieving Network Security Group %q (Resource Group %q)", sgName, resourceGroupName)
+		}
+
+		return res, "Exists", nil
+	}
+}
+
+// wasNetworkSecurityGroupInUse returns whether the NSG couldn't be deleted because it's still
+// associated with a Subnet or Network Interface
+func wasNetworkSecurityGroupInUse(err error) bool {
+	return azureServiceErrorCode(err) == networkSecurityGroupInUseErrorCode
+}
+
+func resourceArmNetworkSecurityGroupRuleHash(v interface{}) int {
+	var buf bytes.Buffer
+	m := normalizeSecurityRulePortRanges(v.(map[string]interface{}))
+
+	buf.WriteString(fmt.Sprintf("%s-", m["name"].(string)))
+	buf.WriteString(fmt.Sprintf("%d-", m["priority"].(int)))
+	buf.WriteString(fmt.Sprintf("%s-", strings.ToLower(m["direction"].(string))))
+	buf.WriteString(fmt.Sprintf("%s-", strings.ToLower(m["access"].(string))))
+	buf.WriteString(fmt.Sprintf("%s-", strings.ToLower(m["protocol"].(string))))
+
+	for _, key := range []string{
+		"description",
+		"source_port_range",
+		"destination_port_range",
+		"source_address_prefix",
+		"destination_address_prefix",
+	} {
+		if v, ok := m[key]; ok {
+			buf.WriteString(fmt.Sprintf("%s-", v.(string)))
+		}
+	}
+
+	// nested sets are hashed by their sorted contents so ordering within them doesn't matter
+	for _, key := range []string{
+		"source_port_ranges",
+		"destination_port_ranges",
+		"source_address_prefixes",
+		"destination_address_prefixes",
+		"source_application_security_group_ids",
+		"destination_application_security_group_ids",
+	} {
+		values := make([]string, 0)
+		switch raw := m[key].(type) {
+		case *schema.Set:
+			for _, v := range raw.List() {
+				values = append(values, v.(string))
+			}
+		case []interface{}:
+			for _, v := range raw {
+				values = append(values, v.(string))
+			}
+		}
+		sort.Strings(values)
+		buf.WriteString(fmt.Sprintf("%s-", strings.Join(values, ",")))
+	}
+
+	return hashcode.String(buf.String())
+}
+
+// changedNetworkSecurityRuleNames returns the sorted names of the Security Rules which have been added, removed or
+// modified - since rules are hashed by their contents, rules which haven't changed are in both sets
+func changedNetworkSecurityRuleNames(old *schema.Set, new *schema.Set) []string {
+	names := make(map[string]struct{})
+	for _, set := range []*schema.Set{old.Difference(new), new.Difference(old)} {
+		for _, v := range set.List() {
+			names[v.(map[string]interface{})["name"].(string)] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// normalizeSecurityRulePortRanges returns a copy of the rule where a comma-separated `source_port_range` or
+// `destination_port_range` is moved into the plural field, since that's how it's sent to (and returned from) Azure
+func normalizeSecurityRulePortRanges(sgRule map[string]interface{}) map[string]interface{} {
+	normalized := make(map[string]interface{}, len(sgRule))
+	for k, v := range sgRule {
+		normalized[k] = v
+	}
+
+	for single, multiple := range map[string]string{
+		"source_port_range":      "source_port_ranges",
+		"destination_port_range": "destination_port_ranges",
+	} {
+		value, ok := sgRule[single].(string)
+		if !ok || !strings.Contains(value, ",") {
+			continue
+		}
+
+		ranges := make([]interface{}, 0)
+		switch raw := sgRule[multiple].(type) {
+		case *schema.Set:
+			ranges = append(ranges, raw.List()...)
+		case []interface{}:
+			ranges = append(ranges, raw...)
+		}
+		for _, r := range splitPortRanges(value) {
+			ranges = append(ranges, r)
+		}
+
+		normalized[single] = ""
+		normalized[multiple] = ranges
+	}
+
+	return normalized
+}
+
+// restoreCommaSeparatedPortRanges puts the ports of rules which were configured using a comma-separated `source_port_range`
+// or `destination_port_range` back into that field - since they're sent to (and returned from) Azure in the plural field,
+// which would otherwise differ from the configuration. Rules are matched by name, and only when the ports are the same.
+func restoreCommaSeparatedPortRanges(rules []interface{}, configured []interface{}) []interface{} {
+	configuredByName := make(map[string]map[string]interface{}, len(configured))
+	for _, raw := range configured {
+		rule := raw.(map[string]interface{})
+		configuredByName[rule["name"].(string)] = rule
+	}
+
+	for _, raw := range rules {
+		rule := raw.(map[string]interface{})
+		config, ok := configuredByName[rule["name"].(string)]
+		if !ok {
+			continue
+		}
+
+		for single, multiple := range map[string]string{
+			"source_port_range":      "source_port_ranges",
+			"destination_port_range": "destination_port_ranges",
+		} {
+			value, _ := config[single].(string)
+			if !strings.Contains(value, ",") {
+				continue
+			}
 
-	return err
+			ranges, ok := rule[multiple].(*schema.Set)
+			if !ok || !ranges.Equal(sliceToSet(splitPortRanges(value))) {
+				continue
+			}
+
+			rule[single] = value
+			rule[multiple] = &schema.Set{F: schema.HashString}
+		}
+	}
+
+	return rules
 }
 
-func flattenNetworkSecurityRules(rules *[]network.SecurityRule) []interface{} {
+// flattenNetworkSecurityRules flattens the Security Rules returned from the API - where a rule's description
+// matches `defaultDescription` it was injected by expandAzureRmSecurityRules, so it's flattened as empty
+// to match the configuration.
+func flattenNetworkSecurityRules(rules *[]network.SecurityRule, defaultDescription string) []interface{} {
 	result := make([]interface{}, 0)
 
 	if rules != nil {
@@ -229,21 +687,40 @@ func flattenNetworkSecurityRules(rules *[]network.SecurityRule) []interface{} {
 				if props.DestinationAddressPrefix != nil {
 					sgRule["destination_address_prefix"] = *props.DestinationAddressPrefix
 				}
+				if props.DestinationAddressPrefixes != nil {
+					sgRule["destination_address_prefixes"] = sliceToSet(*props.DestinationAddressPrefixes)
+				}
 				if props.DestinationPortRange != nil {
 					sgRule["destination_port_range"] = *props.DestinationPortRange
 				}
+				if props.DestinationPortRanges != nil {
+					sgRule["destination_port_ranges"] = sliceToSet(*props.DestinationPortRanges)
+				}
 				if props.SourceAddressPrefix != nil {
 					sgRule["source_address_prefix"] = *props.SourceAddressPrefix
+					sgRule["source_address_prefix_is_service_tag"] = isNetworkSecurityRuleServiceTag(*props.SourceAddressPrefix)
+				}
+				if props.SourceAddressPrefixes != nil {
+					sgRule["source_address_prefixes"] = sliceToSet(*props.SourceAddressPrefixes)
 				}
 				if props.SourcePortRange != nil {
 					sgRule["source_port_range"] = *props.SourcePortRange
 				}
+				if props.SourcePortRanges != nil {
+					sgRule["source_port_ranges"] = sliceToSet(*props.SourcePortRanges)
+				}
+				if props.SourceApplicationSecurityGroups != nil {
+					sgRule["source_application_security_group_ids"] = flattenApplicationSecurityGroupIds(props.SourceApplicationSecurityGroups)
+				}
+				if props.DestinationApplicationSecurityGroups != nil {
+					sgRule["destination_application_security_group_ids"] = flattenApplicationSecurityGroupIds(props.DestinationApplicationSecurityGroups)
+				}
 				sgRule["priority"] = int(*props.Priority)
 				sgRule["access"] = string(props.Access)
 				sgRule["direction"] = string(props.Direction)
 				sgRule["protocol"] = string(props.Protocol)
 
-				if props.Description != nil {
+				if props.Description != nil && (defaultDescription == "" || *props.Description != defaultDescription) {
 					sgRule["description"] = *props.Description
 				}
 			}
@@ -255,40 +732,126 @@ func flattenNetworkSecurityRules(rules *[]network.SecurityRule) []interface{} {
 	return result
 }
 
-func expandAzureRmSecurityRules(d *schema.ResourceData) ([]network.SecurityRule, error) {
-	sgRules := d.Get("security_rule").([]interface{})
+func expandAzureRmSecurityRules(d *schema.ResourceData, warnOnOpenRules bool) ([]network.SecurityRule, error) {
+	sgRules := d.Get("security_rule").(*schema.Set).List()
 	rules := make([]network.SecurityRule, 0)
+	defaultDescription := d.Get("security_rule_default_description").(string)
+
+	// `security_rule` conflicts with this in the config, but holds the rules read from Azure in the state
+	if v := d.Get("security_rules_json").(string); v != "" {
+		parsed, _, errors := parseNetworkSecurityRulesJSON(v, false)
+		if len(errors) > 0 {
+			return nil, multierror.Append(nil, errors...)
+		}
+		sgRules = parsed
+	}
+
+	if err := validateSecurityRulePriorities(sgRules); err != nil {
+		return nil, err
+	}
+
+	if d.Get("warn_on_security_rule_priority_spacing").(bool) {
+		for _, warning := range closelySpacedSecurityRulePriorities(sgRules) {
+			log.Printf("[WARN] %s", warning)
+		}
+	}
 
 	for _, sgRaw := range sgRules {
 		data := sgRaw.(map[string]interface{})
 
+		if err := validateSecurityRule(data); err != nil {
+			return nil, err
+		}
+
+		// rules within `security_rules_json` have already surfaced these at plan time, but the schema can't
+		// validate a `security_rule` block as a whole, so the best we can do for those is to log them
+		for _, warning := range securityRuleWarnings(data, warnOnOpenRules) {
+			log.Printf("[WARN] %s", warning)
+		}
+
 		name := data["name"].(string)
-		source_port_range := data["source_port_range"].(string)
-		destination_port_range := data["destination_port_range"].(string)
-		source_address_prefix := data["source_address_prefix"].(string)
-		destination_address_prefix := data["destination_address_prefix"].(string)
-		priority := int32(data["priority"].(int))
+
+		// the schema validates this too, but check it before the conversion to an int32 so it can never wrap
+		rawPriority := data["priority"].(int)
+		if rawPriority < networkSecurityRuleMinPriority || rawPriority > networkSecurityRuleMaxPriority {
+			return nil, fmt.Errorf("the priority of security rule %q must be between %d and %d, got %d", name, networkSecurityRuleMinPriority, networkSecurityRuleMaxPriority, rawPriority)
+		}
+		priority := int32(rawPriority)
 		access := data["access"].(string)
 		direction := data["direction"].(string)
 		protocol := data["protocol"].(string)
 
 		properties := network.SecurityRulePropertiesFormat{
-			SourcePortRange:          &source_port_range,
-			DestinationPortRange:     &destination_port_range,
-			SourceAddressPrefix:      &source_address_prefix,
-			DestinationAddressPrefix: &destination_address_prefix,
-			Priority:                 &priority,
-			Access:                   network.SecurityRuleAccess(access),
-			Direction:                network.SecurityRuleDirection(direction),
-			Protocol:                 network.SecurityRuleProtocol(protocol),
+			Priority:  &priority,
+			Access:    network.SecurityRuleAccess(access),
+			Direction: network.SecurityRuleDirection(direction),
+			Protocol:  network.SecurityRuleProtocol(protocol),
 		}
 
 		if v := data["description"].(string); v != "" {
 			properties.Description = &v
+		} else if defaultDescription != "" {
+			properties.Description = &defaultDescription
+		}
+
+		if r, ok := data["source_port_ranges"].(*schema.Set); ok && r.Len() > 0 {
+			sourcePortRanges := make([]string, 0)
+			for _, v := range r.List() {
+				sourcePortRanges = append(sourcePortRanges, v.(string))
+			}
+			properties.SourcePortRanges = &sourcePortRanges
+		} else if v := data["source_port_range"].(string); strings.Contains(v, ",") {
+			sourcePortRanges := splitPortRanges(v)
+			properties.SourcePortRanges = &sourcePortRanges
+		} else {
+			source_port_range := data["source_port_range"].(string)
+			properties.SourcePortRange = &source_port_range
+		}
+
+		if r, ok := data["destination_port_ranges"].(*schema.Set); ok && r.Len() > 0 {
+			destinationPortRanges := make([]string, 0)
+			for _, v := range r.List() {
+				destinationPortRanges = append(destinationPortRanges, v.(string))
+			}
+			properties.DestinationPortRanges = &destinationPortRanges
+		} else if v := data["destination_port_range"].(string); strings.Contains(v, ",") {
+			destinationPortRanges := splitPortRanges(v)
+			properties.DestinationPortRanges = &destinationPortRanges
+		} else {
+			destination_port_range := data["destination_port_range"].(string)
+			properties.DestinationPortRange = &destination_port_range
+		}
+
+		if r, ok := data["source_address_prefixes"].(*schema.Set); ok && r.Len() > 0 {
+			sourceAddressPrefixes := make([]string, 0)
+			for _, v := range r.List() {
+				sourceAddressPrefixes = append(sourceAddressPrefixes, v.(string))
+			}
+			properties.SourceAddressPrefixes = &sourceAddressPrefixes
+		} else if r, ok := data["source_application_security_group_ids"].(*schema.Set); ok && r.Len() > 0 {
+			sourceApplicationSecurityGroups := expandApplicationSecurityGroupIds(r)
+			properties.SourceApplicationSecurityGroups = &sourceApplicationSecurityGroups
+		} else {
+			source_address_prefix := data["source_address_prefix"].(string)
+			properties.SourceAddressPrefix = &source_address_prefix
+		}
+
+		if r, ok := data["destination_address_prefixes"].(*schema.Set); ok && r.Len() > 0 {
+			destinationAddressPrefixes := make([]string, 0)
+			for _, v := range r.List() {
+				destinationAddressPrefixes = append(destinationAddressPrefixes, v.(string))
+			}
+			properties.DestinationAddressPrefixes = &destinationAddressPrefixes
+		} else if r, ok := data["destination_application_security_group_ids"].(*schema.Set); ok && r.Len() > 0 {
+			destinationApplicationSecurityGroups := expandApplicationSecurityGroupIds(r)
+			properties.DestinationApplicationSecurityGroups = &destinationApplicationSecurityGroups
+		} else {
+			destination_address_prefix := data["destination_address_prefix"].(string)
+			properties.DestinationAddressPrefix = &destination_address_prefix
 		}
 
 		rule := network.SecurityRule{
-			Name: &name,
+			Name:                         &name,
 			SecurityRulePropertiesFormat: &properties,
 		}
 
@@ -298,13 +861,547 @@ func expandAzureRmSecurityRules(d *schema.ResourceData) ([]network.SecurityRule,
 	return rules, nil
 }
 
-func networkSecurityGroupStateRefreshFunc(client network.SecurityGroupsClient, resourceGroupName string, sgName string) resource.StateRefreshFunc {
-	return func() (interface{}, string, error) {
+// networkSecurityRuleJSON is a Security Rule within `security_rules_json`, which uses the same
+// field names as the `security_rule` block
+type networkSecurityRuleJSON struct {
+	Name                                   string   `json:"name"`
+	Description                            string   `json:"description"`
+	Protocol                               string   `json:"protocol"`
+	SourcePortRange                        string   `json:"source_port_range"`
+	SourcePortRanges                       []string `json:"source_port_ranges"`
+	DestinationPortRange                   string   `json:"destination_port_range"`
+	DestinationPortRanges                  []string `json:"destination_port_ranges"`
+	SourceAddressPrefix                    string   `json:"source_address_prefix"`
+	SourceAddressPrefixes                  []string `json:"source_address_prefixes"`
+	DestinationAddressPrefix               string   `json:"destination_address_prefix"`
+	DestinationAddressPrefixes             []string `json:"destination_address_prefixes"`
+	SourceApplicationSecurityGroupIds      []string `json:"source_application_security_group_ids"`
+	DestinationApplicationSecurityGroupIds []string `json:"destination_application_security_group_ids"`
+	Access                                 string   `json:"access"`
+	Priority                               int      `json:"priority"`
+	Direction                              string   `json:"direction"`
+}
+
+// validateNetworkSecurityRulesJSON validates each rule within `security_rules_json`. The configuration is validated
+// before the provider is configured, so whether to warn about open rules comes from the environment variable.
+func validateNetworkSecurityRulesJSON(v interface{}, k string) (ws []string, errors []error) {
+	_, ws, errors = parseNetworkSecurityRulesJSON(v.(string), openSecurityRuleWarningsEnabledFromEnvironment())
+	return
+}
+
+// parseNetworkSecurityRulesJSON parses a JSON array of Security Rules into the same shape as the `security_rule`
+// block, so they can be expanded in the same way. Each rule is validated as the block would be, and every
+// problem is returned at once prefixed with the index of the rule it was found in.
+func parseNetworkSecurityRulesJSON(input string, warnOnOpenRules bool) (rules []interface{}, ws []string, errors []error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(input), &elements); err != nil {
+		errors = append(errors, fmt.Errorf("security_rules_json must be a JSON array of security rules: %+v", err))
+		return
+	}
+
+	rules = make([]interface{}, 0)
+	for i, element := range elements {
+		prefix := fmt.Sprintf("security_rules_json[%d]", i)
+
+		var rule networkSecurityRuleJSON
+		decoder := json.NewDecoder(bytes.NewReader(element))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&rule); err != nil {
+			errors = append(errors, fmt.Errorf("%s: %+v", prefix, err))
+			continue
+		}
+
+		w, e := validateNetworkSecurityRuleJSON(rule)
+		for _, warning := range w {
+			ws = append(ws, fmt.Sprintf("%s: %s", prefix, warning))
+		}
+		for _, err := range e {
+			errors = append(errors, fmt.Errorf("%s: %+v", prefix, err))
+		}
+
+		sgRule := rule.toSecurityRule()
+		for _, warning := range securityRuleWarnings(sgRule, warnOnOpenRules) {
+			ws = append(ws, fmt.Sprintf("%s: %s", prefix, warning))
+		}
+
+		rules = append(rules, sgRule)
+	}
+
+	return
+}
+
+// validateNetworkSecurityRuleJSON applies the same validation to a rule within `security_rules_json`
+// as the schema applies to a `security_rule` block
+func validateNetworkSecurityRuleJSON(rule networkSecurityRuleJSON) (ws []string, errors []error) {
+	for _, field := range []struct {
+		key   string
+		value string
+	}{
+		{"name", rule.Name},
+		{"protocol", rule.Protocol},
+		{"access", rule.Access},
+		{"direction", rule.Direction},
+	} {
+		if field.value == "" {
+			errors = append(errors, fmt.Errorf("%q is required", field.key))
+		}
+	}
+
+	validate := func(key string, value interface{}, f schema.SchemaValidateFunc) {
+		w, e := f(value, key)
+		ws = append(ws, w...)
+		errors = append(errors, e...)
+	}
+
+	validate("priority", rule.Priority, validation.IntBetween(networkSecurityRuleMinPriority, networkSecurityRuleMaxPriority))
+
+	if rule.Description != "" {
+		validate("description", rule.Description, validateStringLength(networkSecurityRuleDescriptionMaxLength))
+	}
+	if rule.Protocol != "" {
+		validate("protocol", rule.Protocol, validateNetworkSecurityRuleProtocol)
+	}
+	if rule.Access != "" {
+		validate("access", rule.Access, validation.StringInSlice([]string{
+			string(network.SecurityRuleAccessAllow),
+			string(network.SecurityRuleAccessDeny),
+		}, true))
+	}
+	if rule.Direction != "" {
+		validate("direction", rule.Direction, validation.StringInSlice([]string{
+			string(network.SecurityRuleDirectionInbound),
+			string(network.SecurityRuleDirectionOutbound),
+		}, true))
+	}
+
+	for _, field := range []struct {
+		key   string
+		value string
+		f     schema.SchemaValidateFunc
+	}{
+		{"source_port_range", rule.SourcePortRange, validatePortRangeListOrStar},
+		{"destination_port_range", rule.DestinationPortRange, validatePortRangeListOrStar},
+		{"source_address_prefix", rule.SourceAddressPrefix, validateNetworkSecurityRuleAddressPrefix},
+		{"destination_address_prefix", rule.DestinationAddressPrefix, validateNetworkSecurityRuleAddressPrefix},
+	} {
+		if field.value != "" {
+			validate(field.key, field.value, field.f)
+		}
+	}
+
+	for _, field := range []struct {
+		key    string
+		values []string
+		f      schema.SchemaValidateFunc
+	}{
+		{"source_port_ranges", rule.SourcePortRanges, validatePortRangeOrStar},
+		{"destination_port_ranges", rule.DestinationPortRanges, validatePortRangeOrStar},
+		{"source_address_prefixes", rule.SourceAddressPrefixes, validateNetworkSecurityRuleAddressPrefix},
+		{"destination_address_prefixes", rule.DestinationAddressPrefixes, validateNetworkSecurityRuleAddressPrefix},
+	} {
+		for j, value := range field.values {
+			validate(fmt.Sprintf("%s.%d", field.key, j), value, field.f)
+		}
+	}
+
+	return
+}
+
+// toSecurityRule returns the rule in the same shape as an element of the `security_rule` set
+func (rule networkSecurityRuleJSON) toSecurityRule() map[string]interface{} {
+	return map[string]interface{}{
+		"name":                                       rule.Name,
+		"description":                                rule.Description,
+		"protocol":                                   rule.Protocol,
+		"source_port_range":                          rule.SourcePortRange,
+		"source_port_ranges":                         sliceToSet(rule.SourcePortRanges),
+		"destination_port_range":                     rule.DestinationPortRange,
+		"destination_port_ranges":                    sliceToSet(rule.DestinationPortRanges),
+		"source_address_prefix":                      rule.SourceAddressPrefix,
+		"source_address_prefixes":                    sliceToSet(rule.SourceAddressPrefixes),
+		"destination_address_prefix":                 rule.DestinationAddressPrefix,
+		"destination_address_prefixes":               sliceToSet(rule.DestinationAddressPrefixes),
+		"source_application_security_group_ids":      sliceToSet(rule.SourceApplicationSecurityGroupIds),
+		"destination_application_security_group_ids": sliceToSet(rule.DestinationApplicationSecurityGroupIds),
+		"access":    rule.Access,
+		"priority":  rule.Priority,
+		"direction": rule.Direction,
+	}
+}
+
+func validateSecurityRule(sgRule map[string]interface{}) error {
+	var err *multierror.Error
+
+	if description, ok := sgRule["description"].(string); ok && len(description) > networkSecurityRuleDescriptionMaxLength {
+		err = multierror.Append(err, fmt.Errorf(
+			"the description of security rule %q can be no longer than %d characters", sgRule["name"], networkSecurityRuleDescriptionMaxLength))
+	}
+
+	sourcePortRange := sgRule["source_port_range"].(string)
+	sourcePortRanges := sgRule["source_port_ranges"].(*schema.Set)
+	destinationPortRange := sgRule["destination_port_range"].(string)
+	destinationPortRanges := sgRule["destination_port_ranges"].(*schema.Set)
+	sourceAddressPrefix := sgRule["source_address_prefix"].(string)
+	sourceAddressPrefixes := sgRule["source_address_prefixes"].(*schema.Set)
+	destinationAddressPrefix := sgRule["destination_address_prefix"].(string)
+	destinationAddressPrefixes := sgRule["destination_address_prefixes"].(*schema.Set)
+	sourceApplicationSecurityGroupIds := sgRule["source_application_security_group_ids"].(*schema.Set)
+	destinationApplicationSecurityGroupIds := sgRule["destination_application_security_group_ids"].(*schema.Set)
+
+	if sourcePortRange != "" && sourcePortRanges.Len() > 0 {
+		err = multierror.Append(err, fmt.Errorf(
+			"only one of \"source_port_range\" and \"source_port_ranges\" can be used per security rule"))
+	}
+	if destinationPortRange != "" && destinationPortRanges.Len() > 0 {
+		err = multierror.Append(err, fmt.Errorf(
+			"only one of \"destination_port_range\" and \"destination_port_ranges\" can be used per security rule"))
+	}
+	if sourceAddressPrefix != "" && sourceAddressPrefixes.Len() > 0 {
+		err = multierror.Append(err, fmt.Errorf(
+			"only one of \"source_address_prefix\" and \"source_address_prefixes\" can be used per security rule"))
+	}
+	if destinationAddressPrefix != "" && destinationAddressPrefixes.Len() > 0 {
+		err = multierror.Append(err, fmt.Errorf(
+			"only one of \"destination_address_prefix\" and \"destination_address_prefixes\" can be used per security rule"))
+	}
+	if (sourceAddressPrefix != "" || sourceAddressPrefixes.Len() > 0) && sourceApplicationSecurityGroupIds.Len() > 0 {
+		err = multierror.Append(err, fmt.Errorf(
+			"\"source_application_security_group_ids\" cannot be used with \"source_address_prefix\" or \"source_address_prefixes\" in the same security rule"))
+	}
+	if (destinationAddressPrefix != "" || destinationAddressPrefixes.Len() > 0) && destinationApplicationSecurityGroupIds.Len() > 0 {
+		err = multierror.Append(err, fmt.Errorf(
+			"\"destination_application_security_group_ids\" cannot be used with \"destination_address_prefix\" or \"destination_address_prefixes\" in the same security rule"))
+	}
+
+	return err.ErrorOrNil()
+}
+
+// validateSecurityRulePriorities ensures no two rules in the same direction share a priority,
+// since Azure otherwise rejects the whole Network Security Group with an unhelpful error.
+// Every collision is reported at once, grouped by direction, so they can all be fixed together.
+func validateSecurityRulePriorities(sgRules []interface{}) error {
+	type priorityKey struct {
+		direction string
+		priority  int
+	}
+
+	names := make(map[priorityKey][]string)
+	directions := make(map[string]string)
+	keys := make([]priorityKey, 0)
+
+	for _, sgRaw := range sgRules {
+		data := sgRaw.(map[string]interface{})
+
+		direction := data["direction"].(string)
+		key := priorityKey{
+			direction: strings.ToLower(direction),
+			priority:  data["priority"].(int),
+		}
+
+		if _, ok := names[key]; !ok {
+			keys = append(keys, key)
+		}
+		if _, ok := directions[key.direction]; !ok {
+			directions[key.direction] = direction
+		}
+		names[key] = append(names[key], fmt.Sprintf("%q", data["name"].(string)))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].direction != keys[j].direction {
+			return keys[i].direction < keys[j].direction
+		}
+		return keys[i].priority < keys[j].priority
+	})
+
+	var err *multierror.Error
+	for _, key := range keys {
+		rules := names[key]
+		if len(rules) < 2 {
+			continue
+		}
+
+		err = multierror.Append(err, fmt.Errorf(
+			"security rules %s all use priority %d for %s traffic - priorities must be unique per direction",
+			strings.Join(rules, ", "), key.priority, directions[key.direction]))
+	}
+
+	return err.ErrorOrNil()
+}
+
+// closelySpacedSecurityRulePriorities returns a message for each pair of adjacent rules in the same direction whose
+// priorities are less than networkSecurityRulePrioritySpacing apart, which makes it hard to insert rules between them.
+// Duplicate priorities are rejected by validateSecurityRulePriorities, so aren't included.
+func closelySpacedSecurityRulePriorities(sgRules []interface{}) []string {
+	type rule struct {
+		name     string
+		priority int
+	}
+
+	rulesByDirection := make(map[string][]rule)
+	directions := make([]string, 0)
+	for _, sgRaw := range sgRules {
+		data := sgRaw.(map[string]interface{})
+
+		direction := data["direction"].(string)
+		key := strings.ToLower(direction)
+		if _, ok := rulesByDirection[key]; !ok {
+			directions = append(directions, direction)
+		}
+		rulesByDirection[key] = append(rulesByDirection[key], rule{
+			name:     data["name"].(string),
+			priority: data["priority"].(int),
+		})
+	}
+	sort.Strings(directions)
+
+	warnings := make([]string, 0)
+	for _, direction := range directions {
+		rules := rulesByDirection[strings.ToLower(direction)]
+		sort.Slice(rules, func(i, j int) bool {
+			return rules[i].priority < rules[j].priority
+		})
+
+		for i := 1; i < len(rules); i++ {
+			gap := rules[i].priority - rules[i-1].priority
+			if gap > 0 && gap < networkSecurityRulePrioritySpacing {
+				warnings = append(warnings, fmt.Sprintf("the priorities of security rules %q (%d) and %q (%d) for %s traffic are less than %d apart, leaving little room to insert rules between them",
+					rules[i-1].name, rules[i-1].priority, rules[i].name, rules[i].priority, direction, networkSecurityRulePrioritySpacing))
+			}
+		}
+	}
+
+	return warnings
+}
+
+// securityRuleAllowsInboundInternetToAnyPort returns whether the rule allows inbound traffic from
+// any source (or the Internet) to every destination port, which is generally a misconfiguration
+func securityRuleAllowsInboundInternetToAnyPort(sgRule map[string]interface{}) bool {
+	if !strings.EqualFold(sgRule["direction"].(string), string(network.SecurityRuleDirectionInbound)) {
+		return false
+	}
+
+	if !strings.EqualFold(sgRule["access"].(string), string(network.SecurityRuleAccessAllow)) {
+		return false
+	}
+
+	// `*` may also be one of a comma-separated `destination_port_range`, or within `destination_port_ranges`
+	destinationPortRanges := splitPortRanges(sgRule["destination_port_range"].(string))
+	if r, ok := sgRule["destination_port_ranges"].(*schema.Set); ok {
+		for _, v := range r.List() {
+			destinationPortRanges = append(destinationPortRanges, v.(string))
+		}
+	}
+
+	anyPort := false
+	for _, portRange := range destinationPortRanges {
+		if portRange == "*" {
+			anyPort = true
+		}
+	}
+	if !anyPort {
+		return false
+	}
+
+	isInternet := func(prefix string) bool {
+		return prefix == "*" || strings.EqualFold(prefix, "Internet")
+	}
+
+	if isInternet(sgRule["source_address_prefix"].(string)) {
+		return true
+	}
+
+	if prefixes, ok := sgRule["source_address_prefixes"].(*schema.Set); ok {
+		for _, v := range prefixes.List() {
+			if isInternet(v.(string)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// securityRuleWarnings returns a message for each likely mistake in a rule which Azure would accept, namely
+// overlapping address prefixes and (when `warnOnOpenRules` is set) allowing inbound traffic from the Internet to any port
+func securityRuleWarnings(sgRule map[string]interface{}, warnOnOpenRules bool) []string {
+	name := sgRule["name"].(string)
+	warnings := make([]string, 0)
+
+	if warnOnOpenRules && securityRuleAllowsInboundInternetToAnyPort(sgRule) {
+		warnings = append(warnings, fmt.Sprintf("Security Rule %q allows inbound traffic from the Internet to any port", name))
+	}
+
+	for _, key := range []string{"source_address_prefixes", "destination_address_prefixes"} {
+		if r, ok := sgRule[key].(*schema.Set); ok && r.Len() > 1 {
+			prefixes := make([]string, 0)
+			for _, v := range r.List() {
+				prefixes = append(prefixes, v.(string))
+			}
+			sort.Strings(prefixes)
+
+			for _, overlap := range overlappingAddressPrefixes(prefixes) {
+				warnings = append(warnings, fmt.Sprintf("The %s %q and %q of Security Rule %q overlap", key, overlap[0], overlap[1], name))
+			}
+		}
+	}
+
+	return warnings
+}
+
+func expandApplicationSecurityGroupIds(input *schema.Set) []network.ApplicationSecurityGroup {
+	groups := make([]network.ApplicationSecurityGroup, 0)
+	for _, v := range input.List() {
+		id := v.(string)
+		groups = append(groups, network.ApplicationSecurityGroup{
+			ID: &id,
+		})
+	}
+	return groups
+}
+
+func flattenApplicationSecurityGroupIds(groups *[]network.ApplicationSecurityGroup) *schema.Set {
+	ids := &schema.Set{F: schema.HashString}
+	if groups != nil {
+		for _, group := range *groups {
+			if group.ID != nil {
+				ids.Add(*group.ID)
+			}
+		}
+	}
+	return ids
+}
+
+func sliceToSet(slice []string) *schema.Set {
+	set := &schema.Set{F: schema.HashString}
+	for _, v := range slice {
+		set.Add(v)
+	}
+	return set
+}
+
+// createOrUpdateNetworkSecurityGroup retries the request when it's rejected because of a conflicting
+// operation (e.g. a Subnet association in flight) or with a 429, which are common under parallel applies.
+// Other errors (including a 503) aren't retried, since they're unlikely to succeed on a retry
+func createOrUpdateNetworkSecurityGroup(client network.SecurityGroupsClient, resourceGroupName string, sgName string, sg network.SecurityGroup, timeout time.Duration) error {
+	return resource.Retry(timeout, func() *resource.RetryError {
+		respCh, errCh := client.CreateOrUpdate(resourceGroupName, sgName, sg, make(chan struct{}))
+		err := <-errCh
+		resp := <-respCh
+		if err != nil {
+			if response.WasConflict(resp.Response.Response) || response.WasTooManyRequests(resp.Response.Response) {
+				log.Printf("[DEBUG] Retrying creation/update of NSG %q (Resource Group %q): %+v", sgName, resourceGroupName, err)
+				return resource.RetryableError(err)
+			}
+
+			if wasResourceGroupNotFound(err) {
+				return resource.NonRetryableError(resourceGroupNotFoundError(resourceGroupName, err))
+			}
+
+			return resource.NonRetryableError(networkSecurityGroupRequestError(resp.Response.Response, err, "Error creating/updating Network Security Group %q (Resource Group %q)", sgName, resourceGroupName))
+		}
+
+		return nil
+	})
+}
+
+// networkSecurityGroupRequestError includes the ID of the failed request in the error (and logs it),
+// since it's needed by Azure Support to investigate a failure
+func networkSecurityGroupRequestError(resp *http.Response, err error, format string, a ...interface{}) error {
+	message := fmt.Sprintf(format, a...)
+
+	if requestId := response.RequestID(resp); requestId != "" {
+		log.Printf("[ERROR] %s (Request ID %q): %+v", message, requestId, err)
+		return fmt.Errorf("%s (Request ID %q): %+v", message, requestId, err)
+	}
+
+	return fmt.Errorf("%s: %+v", message, err)
+}
+
+// retrieveNetworkSecurityGroupFlowLogStatus returns whether Flow Logs are enabled for the Network Security Group
+// in the Network Watcher for its region. Flow Logs can't be enabled when there's no Network Watcher in the region.
+func retrieveNetworkSecurityGroupFlowLogStatus(meta interface{}, id string, location string) (bool, error) {
+	client := meta.(*ArmClient).watcherClient
+
+	watchers, err := client.ListAll()
+	if err != nil {
+		return false, fmt.Errorf("Error listing Network Watchers: %+v", err)
+	}
+
+	if watchers.Value == nil {
+		return false, nil
+	}
+
+	watcher := findNetworkWatcherForLocation(*watchers.Value, location)
+	if watcher == nil {
+		return false, nil
+	}
+
+	watcherId, err := parseAzureResourceID(*watcher.ID)
+	if err != nil {
+		return false, err
+	}
+	watcherName := watcherId.Path["networkWatchers"]
+
+	parameters := network.FlowLogStatusParameters{
+		TargetResourceID: utils.String(id),
+	}
+	statusChan, errChan := client.GetFlowLogStatus(watcherId.ResourceGroup, watcherName, parameters, make(chan struct{}))
+	status := <-statusChan
+	if err := <-errChan; err != nil {
+		return false, fmt.Errorf("Error retrieving the Flow Log status from Network Watcher %q (Resource Group %q): %+v", watcherName, watcherId.ResourceGroup, err)
+	}
+
+	if props := status.FlowLogProperties; props != nil && props.Enabled != nil {
+		return *props.Enabled, nil
+	}
+
+	return false, nil
+}
+
+// findNetworkWatcherForLocation returns the Network Watcher in the given location, since there's at most one per region
+func findNetworkWatcherForLocation(watchers []network.Watcher, location string) *network.Watcher {
+	for _, watcher := range watchers {
+		if watcher.ID == nil || watcher.Location == nil {
+			continue
+		}
+
+		if azureRMNormalizeLocation(*watcher.Location) == azureRMNormalizeLocation(location) {
+			w := watcher
+			return &w
+		}
+	}
+
+	return nil
+}
+
+// waitForNetworkSecurityGroupToBeAvailable waits for the NSG to finish provisioning and returns it as
+// retrieved by the final poll, so that it doesn't need to be retrieved again (e.g. for its ID)
+func waitForNetworkSecurityGroupToBeAvailable(client network.SecurityGroupsClient, resourceGroupName string, sgName string, timeout time.Duration) (*network.SecurityGroup, error) {
+	log.Printf("[DEBUG] Waiting for NSG (%q) to become available", sgName)
+	var sg network.SecurityGroup
+	stateConf := provisioningStateChangeConf(networkSecurityGroupProvisioningStateGetter(client, resourceGroupName, sgName, &sg), timeout)
+	if _, err := stateConf.WaitForState(); err != nil {
+		return nil, fmt.Errorf("Error waiting for NSG (%q) to become available: %+v", sgName, err)
+	}
+
+	return &sg, nil
+}
+
+// networkSecurityGroupProvisioningStateGetter returns the Provisioning State of the NSG, storing
+// the NSG that was retrieved in `latest`
+func networkSecurityGroupProvisioningStateGetter(client network.SecurityGroupsClient, resourceGroupName string, sgName string, latest *network.SecurityGroup) provisioningStateGetter {
+	return func() (string, error) {
 		res, err := client.Get(resourceGroupName, sgName, "")
 		if err != nil {
-			return nil, "", fmt.Errorf("Error issuing read request in networkSecurityGroupStateRefreshFunc for NSG '%s' (RG: '%s'): %+v", sgName, resourceGroupName, err)
+			return "", fmt.Errorf("Error issuing read request for NSG '%s' (RG: '%s'): %+v", sgName, resourceGroupName, err)
+		}
+		*latest = res
+
+		if props := res.SecurityGroupPropertiesFormat; props != nil && props.ProvisioningState != nil {
+			return *props.ProvisioningState, nil
 		}
 
-		return res, *res.SecurityGroupPropertiesFormat.ProvisioningState, nil
+		return "", fmt.Errorf("Error reading the Provisioning State of NSG '%s' (RG: '%s')", sgName, resourceGroupName)
 	}
 }
@@ -0,0 +1,79 @@
+package azurerm
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resourceAzureRMNetworkSecurityGroupMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found AzureRM Network Security Group State v0; migrating to v1")
+		return migrateAzureRMNetworkSecurityGroupStateV0toV1(is)
+	default:
+		return is, fmt.Errorf("Unexpected schema version: %d", v)
+	}
+}
+
+// migrateAzureRMNetworkSecurityGroupStateV0toV1 re-keys the `security_rule` block from list indexes
+// (e.g. `security_rule.0.name`) to the hash of each rule, since it's now a Set
+func migrateAzureRMNetworkSecurityGroupStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() {
+		log.Println("[DEBUG] Empty InstanceState; nothing to migrate.")
+		return is, nil
+	}
+
+	log.Printf("[DEBUG] ARM Network Security Group Attributes before Migration: %#v", is.Attributes)
+
+	if err := migrateAzureRMNetworkSecurityGroupStateV0toV1SecurityRules(is); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] ARM Network Security Group Attributes after State Migration: %#v", is.Attributes)
+
+	return is, nil
+}
+
+func migrateAzureRMNetworkSecurityGroupStateV0toV1SecurityRules(is *terraform.InstanceState) error {
+	networkSecurityGroupSchema := resourceArmNetworkSecurityGroup().Schema
+	reader := &schema.MapFieldReader{
+		Schema: networkSecurityGroupSchema,
+		Map:    schema.BasicMapReader(is.Attributes),
+	}
+
+	// the reader treats each list index as the key of a set item, so the rules are rehashed as they're read
+	result, err := reader.ReadField([]string{"security_rule"})
+	if err != nil {
+		return err
+	}
+
+	if !result.Exists {
+		return nil
+	}
+
+	rules := result.Value.(*schema.Set)
+
+	// remove the existing fields
+	for k := range is.Attributes {
+		if strings.HasPrefix(k, "security_rule.") {
+			delete(is.Attributes, k)
+		}
+	}
+
+	// write this out
+	writer := schema.MapFieldWriter{
+		Schema: networkSecurityGroupSchema,
+	}
+	if err := writer.WriteField([]string{"security_rule"}, rules); err != nil {
+		return err
+	}
+	for k, v := range writer.Map() {
+		is.Attributes[k] = v
+	}
+
+	return nil
+}
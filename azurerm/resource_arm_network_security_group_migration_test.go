@@ -0,0 +1,132 @@
+package azurerm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAzureRMNetworkSecurityGroupMigrateState(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "some_id",
+		Attributes: map[string]string{
+			"name":                                                    "acctestnsg",
+			"security_rule.#":                                         "2",
+			"security_rule.0.name":                                    "allow-ssh",
+			"security_rule.0.description":                             "",
+			"security_rule.0.protocol":                                "tcp",
+			"security_rule.0.source_port_range":                       "*",
+			"security_rule.0.destination_port_range":                  "22",
+			"security_rule.0.source_address_prefix":                   "VirtualNetwork",
+			"security_rule.0.destination_address_prefix":              "*",
+			"security_rule.0.access":                                  "Allow",
+			"security_rule.0.priority":                                "100",
+			"security_rule.0.direction":                               "Inbound",
+			"security_rule.1.name":                                    "allow-web",
+			"security_rule.1.description":                             "",
+			"security_rule.1.protocol":                                "tcp",
+			"security_rule.1.source_port_range":                       "*",
+			"security_rule.1.destination_port_ranges.#":               "2",
+			"security_rule.1.destination_port_ranges.0":               "80",
+			"security_rule.1.destination_port_ranges.1":               "443",
+			"security_rule.1.source_address_prefix":                   "",
+			"security_rule.1.source_address_prefixes.#":               "2",
+			"security_rule.1.source_address_prefixes.0":               "10.0.0.0/24",
+			"security_rule.1.source_address_prefixes.1":               "10.0.1.0/24",
+			"security_rule.1.destination_address_prefix":              "*",
+			"security_rule.1.access":                                  "Allow",
+			"security_rule.1.priority":                                "110",
+			"security_rule.1.direction":                               "Inbound",
+			"security_rule.1.destination_port_range":                  "",
+			"security_rule.1.source_application_security_group_ids.#": "0",
+		},
+	}
+
+	// these are the rules as they'd be read from the configuration
+	expected := []interface{}{
+		testNetworkSecurityGroupRule(map[string]interface{}{
+			"name":                       "allow-ssh",
+			"protocol":                   "tcp",
+			"destination_port_range":     "22",
+			"source_address_prefix":      "VirtualNetwork",
+			"destination_address_prefix": "*",
+			"priority":                   100,
+		}),
+		testNetworkSecurityGroupRule(map[string]interface{}{
+			"name":                       "allow-web",
+			"protocol":                   "tcp",
+			"destination_port_range":     "",
+			"destination_port_ranges":    schema.NewSet(schema.HashString, []interface{}{"443", "80"}),
+			"source_address_prefixes":    schema.NewSet(schema.HashString, []interface{}{"10.0.1.0/24", "10.0.0.0/24"}),
+			"destination_address_prefix": "*",
+			"priority":                   110,
+		}),
+	}
+
+	is, err := resourceAzureRMNetworkSecurityGroupMigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("bad: %+v", err)
+	}
+
+	if v := is.Attributes["name"]; v != "acctestnsg" {
+		t.Fatalf("Expected the `name` to be unchanged but got %q", v)
+	}
+
+	if v := is.Attributes["security_rule.#"]; v != "2" {
+		t.Fatalf("Expected 2 Security Rules but got %q", v)
+	}
+
+	for _, rule := range expected {
+		hash := resourceArmNetworkSecurityGroupRuleHash(rule)
+		name := rule.(map[string]interface{})["name"].(string)
+
+		key := fmt.Sprintf("security_rule.%d.name", hash)
+		if v := is.Attributes[key]; v != name {
+			t.Fatalf("Expected %q to be %q but got %q - attributes: %+v", key, name, v, is.Attributes)
+		}
+	}
+
+	for k := range is.Attributes {
+		if strings.HasPrefix(k, "security_rule.0.") || strings.HasPrefix(k, "security_rule.1.") {
+			t.Fatalf("Expected the list index %q to have been removed", k)
+		}
+	}
+
+	// reading the migrated state back should give the same set as the configuration, and so no diff
+	reader := &schema.MapFieldReader{
+		Schema: resourceArmNetworkSecurityGroup().Schema,
+		Map:    schema.BasicMapReader(is.Attributes),
+	}
+	result, err := reader.ReadField([]string{"security_rule"})
+	if err != nil {
+		t.Fatalf("Error reading the migrated state: %+v", err)
+	}
+
+	actual := result.Value.(*schema.Set)
+	config := schema.NewSet(resourceArmNetworkSecurityGroupRuleHash, expected)
+	if actual.Difference(config).Len() != 0 || config.Difference(actual).Len() != 0 {
+		t.Fatalf("Expected the migrated Security Rules to match the configuration - got %+v", actual.List())
+	}
+}
+
+func TestAzureRMNetworkSecurityGroupMigrateState_empty(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "some_id",
+		Attributes: map[string]string{
+			"name":            "acctestnsg",
+			"security_rule.#": "0",
+		},
+	}
+
+	is, err := resourceAzureRMNetworkSecurityGroupMigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("bad: %+v", err)
+	}
+
+	if v := is.Attributes["security_rule.#"]; v != "0" {
+		t.Fatalf("Expected no Security Rules but got %q", v)
+	}
+}
@@ -2,10 +2,20 @@ package azurerm
 
 import (
 	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/Azure/azure-sdk-for-go/arm/network"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform/config"
 	"github.com/hashicorp/terraform/helper/acctest"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
 )
@@ -22,6 +32,7 @@ func TestAccAzureRMNetworkSecurityGroup_basic(t *testing.T) {
 				Config: testAccAzureRMNetworkSecurityGroup_basic(rInt, testLocation()),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "default_security_rule.#", "6"),
 				),
 			},
 		},
@@ -62,90 +73,1406 @@ func TestAccAzureRMNetworkSecurityGroup_update(t *testing.T) {
 				),
 			},
 			{
+				// omitting the `security_rule` blocks leaves the existing rules in place
 				Config: testAccAzureRMNetworkSecurityGroup_basic(rInt, location),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_noRules(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_noRules(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_count", "0"),
+					resource.TestCheckResourceAttr(resourceName, "highest_priority", "0"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_names.#", "0"),
+					resource.TestCheckResourceAttr(resourceName, "default_security_rule.#", "6"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_removeAllRules(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+	location := testLocation()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_singleRule(rInt, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "1"),
+				),
+			},
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_noRules(rInt, location),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "0"),
 				),
 			},
 		},
-	})
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_securityRulesJson(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_securityRulesJson(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_count", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_updateRule(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+	location := testLocation()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_twoRules(rInt, location, "22"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "2"),
+					testCheckAzureRMNetworkSecurityGroupRuleDestinationPortRange(resourceName, "ssh", "22"),
+					testCheckAzureRMNetworkSecurityGroupRuleDestinationPortRange(resourceName, "https", "443"),
+				),
+			},
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_twoRules(rInt, location, "2222"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "2"),
+					testCheckAzureRMNetworkSecurityGroupRuleDestinationPortRange(resourceName, "ssh", "2222"),
+					testCheckAzureRMNetworkSecurityGroupRuleDestinationPortRange(resourceName, "https", "443"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_disappears(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_basic(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					testCheckAzureRMNetworkSecurityGroupDisappears(resourceName),
+				),
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_withTags(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_withTags(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "tags.environment", "Production"),
+					resource.TestCheckResourceAttr(resourceName, "tags.cost_center", "MSFT"),
+				),
+			},
+
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_withTagsUpdate(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags.environment", "staging"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_addingExtraRules(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_singleRule(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_count", "1"),
+					resource.TestCheckResourceAttr(resourceName, "highest_priority", "100"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_names.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_names.0", "test123"),
+				),
+			},
+
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_anotherRule(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_count", "2"),
+					resource.TestCheckResourceAttr(resourceName, "highest_priority", "101"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_names.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_names.0", "test123"),
+					resource.TestCheckResourceAttr(resourceName, "security_rule_names.1", "testDeny"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccAzureRMNetworkSecurityGroup_augmented(t *testing.T) {
+	resourceName := "azurerm_network_security_group.test"
+	rInt := acctest.RandInt()
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAzureRMNetworkSecurityGroup_augmented(rInt, testLocation()),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "1"),
+					testCheckAzureRMNetworkSecurityGroupAugmentedRule(resourceName, 1, 2, 2, 2),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceAzureRMNetworkSecurityGroupRule_validation(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Rule     map[string]interface{}
+		ErrCount int
+	}{
+		{
+			Name: "Address Prefix",
+			Rule: map[string]interface{}{
+				"source_address_prefix":      "*",
+				"destination_address_prefix": "*",
+			},
+			ErrCount: 0,
+		},
+		{
+			Name: "Address Prefix and Address Prefixes",
+			Rule: map[string]interface{}{
+				"source_address_prefix":   "10.0.0.0/24",
+				"source_address_prefixes": []interface{}{"10.0.1.0/24"},
+			},
+			ErrCount: 1,
+		},
+		{
+			Name: "Application Security Groups",
+			Rule: map[string]interface{}{
+				"source_application_security_group_ids":      []interface{}{"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Network/applicationSecurityGroups/asg1"},
+				"destination_application_security_group_ids": []interface{}{"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Network/applicationSecurityGroups/asg2"},
+			},
+			ErrCount: 0,
+		},
+		{
+			Name: "Address Prefix and Application Security Groups",
+			Rule: map[string]interface{}{
+				"source_address_prefix":                      "*",
+				"source_application_security_group_ids":      []interface{}{"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Network/applicationSecurityGroups/asg1"},
+				"destination_address_prefixes":               []interface{}{"10.0.0.0/24"},
+				"destination_application_security_group_ids": []interface{}{"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/group1/providers/Microsoft.Network/applicationSecurityGroups/asg2"},
+			},
+			ErrCount: 2,
+		},
+		{
+			Name: "Description at the Limit",
+			Rule: map[string]interface{}{
+				"description": strings.Repeat("a", 140),
+			},
+			ErrCount: 0,
+		},
+		{
+			Name: "Description over the Limit",
+			Rule: map[string]interface{}{
+				"description": strings.Repeat("a", 141),
+			},
+			ErrCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		rule := testNetworkSecurityGroupRule(tc.Rule)
+		err := validateSecurityRule(rule)
+
+		errCount := 0
+		if err != nil {
+			errCount = len(err.(*multierror.Error).Errors)
+		}
+
+		if errCount != tc.ErrCount {
+			t.Fatalf("Expected %q to trigger %d validation errors but got %d: %+v", tc.Name, tc.ErrCount, errCount, err)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroupRule_priorities(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Rules    []map[string]interface{}
+		ErrCount int
+	}{
+		{
+			Name: "Unique Priorities",
+			Rules: []map[string]interface{}{
+				{"name": "rule1", "priority": 100, "direction": "Inbound"},
+				{"name": "rule2", "priority": 200, "direction": "Inbound"},
+			},
+			ErrCount: 0,
+		},
+		{
+			Name: "Same Priority in Different Directions",
+			Rules: []map[string]interface{}{
+				{"name": "rule1", "priority": 100, "direction": "Inbound"},
+				{"name": "rule2", "priority": 100, "direction": "Outbound"},
+			},
+			ErrCount: 0,
+		},
+		{
+			Name: "Same Priority in the Same Direction",
+			Rules: []map[string]interface{}{
+				{"name": "rule1", "priority": 100, "direction": "Inbound"},
+				{"name": "rule2", "priority": 100, "direction": "inbound"},
+				{"name": "rule3", "priority": 100, "direction": "Outbound"},
+				{"name": "rule4", "priority": 100, "direction": "Outbound"},
+			},
+			ErrCount: 2,
+		},
+		{
+			Name: "Three Rules sharing a Priority",
+			Rules: []map[string]interface{}{
+				{"name": "rule1", "priority": 100, "direction": "Inbound"},
+				{"name": "rule2", "priority": 100, "direction": "Inbound"},
+				{"name": "rule3", "priority": 100, "direction": "Inbound"},
+			},
+			ErrCount: 1,
+		},
+		{
+			Name: "Multiple Collisions",
+			Rules: []map[string]interface{}{
+				{"name": "rule1", "priority": 200, "direction": "Inbound"},
+				{"name": "rule2", "priority": 100, "direction": "Inbound"},
+				{"name": "rule3", "priority": 200, "direction": "Inbound"},
+				{"name": "rule4", "priority": 100, "direction": "Inbound"},
+				{"name": "rule5", "priority": 100, "direction": "Outbound"},
+				{"name": "rule6", "priority": 100, "direction": "Outbound"},
+			},
+			ErrCount: 3,
+		},
+	}
+
+	for _, tc := range cases {
+		rules := make([]interface{}, 0)
+		for _, r := range tc.Rules {
+			rules = append(rules, testNetworkSecurityGroupRule(r))
+		}
+
+		err := validateSecurityRulePriorities(rules)
+
+		errCount := 0
+		if err != nil {
+			errCount = len(err.(*multierror.Error).Errors)
+		}
+
+		if errCount != tc.ErrCount {
+			t.Fatalf("Expected %q to trigger %d validation errors but got %d: %+v", tc.Name, tc.ErrCount, errCount, err)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroupRule_prioritiesGrouped(t *testing.T) {
+	rules := []interface{}{
+		testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule1", "priority": 100, "direction": "Outbound"}),
+		testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule2", "priority": 100, "direction": "Inbound"}),
+		testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule3", "priority": 100, "direction": "Inbound"}),
+		testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule4", "priority": 100, "direction": "Inbound"}),
+		testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule5", "priority": 100, "direction": "Outbound"}),
+	}
+
+	err := validateSecurityRulePriorities(rules)
+	if err == nil {
+		t.Fatalf("Expected the colliding priorities to return an error")
+	}
+
+	errors := err.(*multierror.Error).Errors
+	expected := []string{
+		`security rules "rule2", "rule3", "rule4" all use priority 100 for Inbound traffic - priorities must be unique per direction`,
+		`security rules "rule1", "rule5" all use priority 100 for Outbound traffic - priorities must be unique per direction`,
+	}
+
+	if len(errors) != len(expected) {
+		t.Fatalf("Expected %d errors but got %d: %+v", len(expected), len(errors), err)
+	}
+
+	for i, v := range expected {
+		if errors[i].Error() != v {
+			t.Fatalf("Expected error %d to be %q but got %q", i, v, errors[i].Error())
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroupRule_hash(t *testing.T) {
+	first := testNetworkSecurityGroupRule(map[string]interface{}{
+		"protocol":                "Tcp",
+		"direction":               "Inbound",
+		"destination_port_ranges": []interface{}{"22", "80"},
+	})
+	second := testNetworkSecurityGroupRule(map[string]interface{}{
+		"protocol":                "tcp",
+		"direction":               "inbound",
+		"destination_port_ranges": []interface{}{"80", "22"},
+	})
+	third := testNetworkSecurityGroupRule(map[string]interface{}{
+		"protocol":                "Tcp",
+		"direction":               "Inbound",
+		"destination_port_ranges": []interface{}{"22", "443"},
+	})
+
+	if resourceArmNetworkSecurityGroupRuleHash(first) != resourceArmNetworkSecurityGroupRuleHash(second) {
+		t.Fatalf("Expected rules differing only by casing and ordering to have the same hash")
+	}
+
+	if resourceArmNetworkSecurityGroupRuleHash(first) == resourceArmNetworkSecurityGroupRuleHash(third) {
+		t.Fatalf("Expected rules with different port ranges to have different hashes")
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroupRule_hashCommaSeparatedPorts(t *testing.T) {
+	config := testNetworkSecurityGroupRule(map[string]interface{}{
+		"destination_port_range": "80, 443",
+	})
+	state := testNetworkSecurityGroupRule(map[string]interface{}{
+		"destination_port_range":  "",
+		"destination_port_ranges": []interface{}{"443", "80"},
+	})
+
+	if resourceArmNetworkSecurityGroupRuleHash(config) != resourceArmNetworkSecurityGroupRuleHash(state) {
+		t.Fatalf("Expected a comma-separated destination_port_range to have the same hash as the equivalent destination_port_ranges")
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_commaSeparatedPortsHaveNoDiff(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestrg/providers/Microsoft.Network/networkSecurityGroups/acctestnsg"
+
+	// Azure returns a comma-separated port range in the plural field
+	client := network.NewSecurityGroupsClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	client.Sender = testAzureResponseSender(http.StatusOK, fmt.Sprintf(`{"id": %q, "name": "acctestnsg", "location": "westeurope", "properties": {"securityRules": [{"name": "web", "properties": {"protocol": "Tcp", "sourcePortRange": "*", "destinationPortRanges": ["80", "443"], "sourceAddressPrefix": "*", "destinationAddressPrefix": "*", "access": "Allow", "priority": 100, "direction": "Inbound"}}]}}`, id))
+	meta := &ArmClient{
+		secGroupClient: client,
+	}
+
+	rule := map[string]interface{}{
+		"name":                       "web",
+		"protocol":                   "Tcp",
+		"source_port_range":          "*",
+		"destination_port_range":     "80,443",
+		"source_address_prefix":      "*",
+		"destination_address_prefix": "*",
+		"access":                     "Allow",
+		"priority":                   100,
+		"direction":                  "Inbound",
+	}
+	raw := map[string]interface{}{
+		"name":                "acctestnsg",
+		"resource_group_name": "acctestrg",
+		"location":            "westeurope",
+		"security_rule":       []interface{}{rule},
+	}
+
+	// the state after applying the configuration, which is then refreshed
+	r := resourceArmNetworkSecurityGroup()
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.SetId(id)
+	state := d.State()
+	state.Meta = map[string]interface{}{
+		"schema_version": "1",
+	}
+
+	refreshed, err := r.Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("Error reading the Network Security Group: %+v", err)
+	}
+
+	for k, v := range refreshed.Attributes {
+		if strings.HasSuffix(k, ".destination_port_range") && v != "80,443" {
+			t.Fatalf("Expected %q to keep the configured value %q but got %q", k, "80,443", v)
+		}
+		if strings.HasSuffix(k, ".destination_port_ranges.#") && v != "0" {
+			t.Fatalf("Expected %q to be empty but got %q", k, v)
+		}
+	}
+
+	rawConfig, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("Error building the configuration: %+v", err)
+	}
+
+	diff, err := r.Diff(refreshed, terraform.NewResourceConfig(rawConfig))
+	if err != nil {
+		t.Fatalf("Error diffing: %+v", err)
+	}
+
+	if !diff.Empty() {
+		t.Fatalf("Expected no diff for a comma-separated `destination_port_range` but got %+v", diff)
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroupRule_flattenIPv6Prefixes(t *testing.T) {
+	rules := []network.SecurityRule{
+		{
+			Name: utils.String("ipv6"),
+			SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
+				Priority:                   utils.Int32(100),
+				SourceAddressPrefix:        utils.String("2001:DB8::/32"),
+				DestinationAddressPrefixes: &[]string{"2001:DB8:0:1::/64", "fd00::1"},
+			},
+		},
+	}
+
+	flattened := flattenNetworkSecurityRules(&rules, "")
+	if len(flattened) != 1 {
+		t.Fatalf("Expected 1 flattened rule but got %d", len(flattened))
+	}
+
+	rule := flattened[0].(map[string]interface{})
+	if v := rule["source_address_prefix"].(string); v != "2001:DB8::/32" {
+		t.Fatalf("Expected the source address prefix %q to be preserved but got %q", "2001:DB8::/32", v)
+	}
+
+	if rule["source_address_prefix_is_service_tag"].(bool) {
+		t.Fatalf("Expected the source address prefix %q not to be a Service Tag", "2001:DB8::/32")
+	}
+
+	destinations := rule["destination_address_prefixes"].(*schema.Set)
+	for _, v := range []string{"2001:DB8:0:1::/64", "fd00::1"} {
+		if !destinations.Contains(v) {
+			t.Fatalf("Expected the destination address prefixes to contain %q: %+v", v, destinations.List())
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroupRule_hashIgnoresServiceTagFlag(t *testing.T) {
+	config := testNetworkSecurityGroupRule(map[string]interface{}{
+		"source_address_prefix": "VirtualNetwork",
+	})
+	state := testNetworkSecurityGroupRule(map[string]interface{}{
+		"source_address_prefix":                "VirtualNetwork",
+		"source_address_prefix_is_service_tag": true,
+	})
+
+	if resourceArmNetworkSecurityGroupRuleHash(config) != resourceArmNetworkSecurityGroupRuleHash(state) {
+		t.Fatalf("Expected the computed `source_address_prefix_is_service_tag` not to change the hash of a rule")
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_expandPriorityBounds(t *testing.T) {
+	cases := []struct {
+		Priority    int
+		ExpectError bool
+	}{
+		{Priority: networkSecurityRuleMinPriority, ExpectError: false},
+		{Priority: networkSecurityRuleMaxPriority, ExpectError: false},
+		{Priority: networkSecurityRuleMinPriority - 1, ExpectError: true},
+		{Priority: networkSecurityRuleMaxPriority + 1, ExpectError: true},
+		// the default rules start at 65000
+		{Priority: 65000, ExpectError: true},
+		// this would wrap around to 100 if it were converted to an int32 without being checked
+		{Priority: 1<<32 + 100, ExpectError: true},
+	}
+
+	for _, tc := range cases {
+		d := schema.TestResourceDataRaw(t, resourceArmNetworkSecurityGroup().Schema, map[string]interface{}{
+			"name":                "acctestnsg",
+			"location":            "westeurope",
+			"resource_group_name": "acctestRG",
+			"security_rule": []interface{}{
+				map[string]interface{}{
+					"name":                       "rule1",
+					"protocol":                   "Tcp",
+					"source_port_range":          "*",
+					"destination_port_range":     "*",
+					"source_address_prefix":      "*",
+					"destination_address_prefix": "*",
+					"access":                     "Allow",
+					"priority":                   tc.Priority,
+					"direction":                  "Inbound",
+				},
+			},
+		})
+
+		rules, err := expandAzureRmSecurityRules(d, false)
+		if tc.ExpectError {
+			if err == nil {
+				t.Fatalf("Expected an error for the priority %d but didn't get one", tc.Priority)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("Expected no error for the priority %d but got: %+v", tc.Priority, err)
+		}
+
+		if len(rules) != 1 || int(*rules[0].Priority) != tc.Priority {
+			t.Fatalf("Expected a single rule with the priority %d but got %+v", tc.Priority, rules)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_defaultRuleDescription(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceArmNetworkSecurityGroup().Schema, map[string]interface{}{
+		"name":                              "acctestnsg",
+		"resource_group_name":               "acctestrg",
+		"location":                          "westeurope",
+		"security_rule_default_description": "Managed by Terraform",
+		"security_rule": []interface{}{
+			map[string]interface{}{
+				"name":                       "rule1",
+				"protocol":                   "Tcp",
+				"source_port_range":          "*",
+				"destination_port_range":     "*",
+				"source_address_prefix":      "*",
+				"destination_address_prefix": "*",
+				"access":                     "Allow",
+				"priority":                   100,
+				"direction":                  "Inbound",
+			},
+			map[string]interface{}{
+				"name":                       "rule2",
+				"description":                "Allow SSH",
+				"protocol":                   "Tcp",
+				"source_port_range":          "*",
+				"destination_port_range":     "22",
+				"source_address_prefix":      "*",
+				"destination_address_prefix": "*",
+				"access":                     "Allow",
+				"priority":                   110,
+				"direction":                  "Inbound",
+			},
+		},
+	})
+
+	rules, err := expandAzureRmSecurityRules(d, false)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %+v", err)
+	}
+
+	expected := map[string]string{
+		"rule1": "Managed by Terraform",
+		"rule2": "Allow SSH",
+	}
+	for _, rule := range rules {
+		if rule.Description == nil || *rule.Description != expected[*rule.Name] {
+			t.Fatalf("Expected the description of %q to be %q but got %+v", *rule.Name, expected[*rule.Name], rule.Description)
+		}
+	}
+
+	// the default description shouldn't come back from the API as a diff against the configuration
+	flattened := flattenNetworkSecurityRules(&rules, "Managed by Terraform")
+	for _, raw := range flattened {
+		rule := raw.(map[string]interface{})
+		description, _ := rule["description"].(string)
+		if rule["name"] == "rule1" && description != "" {
+			t.Fatalf("Expected the default description of %q to be flattened as empty but got %q", rule["name"], description)
+		}
+		if rule["name"] == "rule2" && description != "Allow SSH" {
+			t.Fatalf("Expected the description of %q to be %q but got %q", rule["name"], "Allow SSH", description)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_parseSecurityRulesJSON(t *testing.T) {
+	rules, _, errors := parseNetworkSecurityRulesJSON(`[
+  {
+    "name": "ssh",
+    "priority": 100,
+    "direction": "Inbound",
+    "access": "Allow",
+    "protocol": "Tcp",
+    "source_port_range": "*",
+    "destination_port_range": "22",
+    "source_address_prefixes": ["10.0.0.0/24", "10.0.1.0/24"],
+    "destination_address_prefix": "*"
+  }
+]`, false)
+	if len(errors) > 0 {
+		t.Fatalf("Expected no errors but got: %+v", errors)
+	}
+	if len(rules) != 1 {
+		t.Fatalf("Expected 1 rule but got %d", len(rules))
+	}
+
+	rule := rules[0].(map[string]interface{})
+	if rule["name"] != "ssh" || rule["priority"] != 100 || rule["destination_port_range"] != "22" {
+		t.Fatalf("Unexpected rule: %+v", rule)
+	}
+	if prefixes := rule["source_address_prefixes"].(*schema.Set); prefixes.Len() != 2 {
+		t.Fatalf("Expected 2 source_address_prefixes but got %d", prefixes.Len())
+	}
+	if err := validateSecurityRule(rule); err != nil {
+		t.Fatalf("Expected the parsed rule to be valid but got: %+v", err)
+	}
+
+	cases := []struct {
+		Value    string
+		Expected []string
+	}{
+		{
+			Value:    `{"name": "ssh"}`,
+			Expected: []string{"security_rules_json must be a JSON array of security rules"},
+		},
+		{
+			Value:    `[{"name": "ssh", "priority": 100, "direction": "Inbound", "access": "Allow", "protocol": "Tcp", "port": "22"}]`,
+			Expected: []string{`security_rules_json[0]: json: unknown field "port"`},
+		},
+		{
+			Value:    `[{"name": "ssh", "priority": "100", "direction": "Inbound", "access": "Allow", "protocol": "Tcp"}]`,
+			Expected: []string{"security_rules_json[0]: json: cannot unmarshal string"},
+		},
+		{
+			Value: `[
+  {"name": "ssh", "priority": 100, "direction": "Inbound", "access": "Allow", "protocol": "Tcp"},
+  {"name": "http", "priority": 5000, "direction": "Sideways", "access": "Allow", "protocol": "Tcp", "destination_port_ranges": ["80", "90-80"]},
+  {"priority": 110, "direction": "Inbound", "access": "Allow", "protocol": "Tcp", "source_address_prefix": "10.0.0.0/33"}
+]`,
+			Expected: []string{
+				"security_rules_json[1]: expected priority to be in the range (100 - 4096), got 5000",
+				"security_rules_json[1]: expected direction to be one of [Inbound Outbound], got Sideways",
+				`security_rules_json[1]: "destination_port_ranges.1" must have the lower port first in a range of ports, got "90-80"`,
+				`security_rules_json[2]: "name" is required`,
+				`security_rules_json[2]: "source_address_prefix" must be a CIDR, an IP Address, ` + "`*`" + ` or a Service Tag, got "10.0.0.0/33"`,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		_, _, errors := parseNetworkSecurityRulesJSON(tc.Value, false)
+		if len(errors) != len(tc.Expected) {
+			t.Fatalf("Expected %d errors for %s but got %d: %+v", len(tc.Expected), tc.Value, len(errors), errors)
+		}
+
+		for i, expected := range tc.Expected {
+			if !strings.HasPrefix(errors[i].Error(), expected) {
+				t.Fatalf("Expected error %d for %s to start with %q but got %q", i, tc.Value, expected, errors[i].Error())
+			}
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_securityRulesJSONConflictsWithSecurityRule(t *testing.T) {
+	raw := map[string]interface{}{
+		"name":                "acctestnsg",
+		"resource_group_name": "acctestrg",
+		"location":            "westeurope",
+		"security_rules_json": `[{"name": "from-json", "priority": 200, "direction": "Outbound", "access": "Deny", "protocol": "*", "source_port_range": "*", "destination_port_range": "*", "source_address_prefix": "*", "destination_address_prefix": "Internet"}]`,
+		"security_rule": []interface{}{
+			map[string]interface{}{
+				"name":                       "inline",
+				"protocol":                   "Tcp",
+				"source_port_range":          "*",
+				"destination_port_range":     "*",
+				"source_address_prefix":      "*",
+				"destination_address_prefix": "*",
+				"access":                     "Allow",
+				"priority":                   100,
+				"direction":                  "Inbound",
+			},
+		},
+	}
+
+	rawConfig, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatalf("Error building the raw config: %+v", err)
+	}
+
+	_, errors := resourceArmNetworkSecurityGroup().Validate(terraform.NewResourceConfig(rawConfig))
+	if len(errors) != 2 {
+		t.Fatalf("Expected `security_rule` and `security_rules_json` to conflict with each other but got: %+v", errors)
+	}
+	for _, err := range errors {
+		if !strings.Contains(err.Error(), "conflicts with") {
+			t.Fatalf("Expected a conflict error but got: %+v", err)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_expandSecurityRulesJSON(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceArmNetworkSecurityGroup().Schema, map[string]interface{}{
+		"name":                "acctestnsg",
+		"resource_group_name": "acctestrg",
+		"location":            "westeurope",
+		"security_rules_json": `[{"name": "from-json", "priority": 200, "direction": "Outbound", "access": "Deny", "protocol": "*", "source_port_range": "*", "destination_port_range": "80,443", "source_address_prefix": "*", "destination_address_prefix": "Internet"}]`,
+	})
+
+	rules, err := expandAzureRmSecurityRules(d, false)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %+v", err)
+	}
+	if len(rules) != 1 {
+		t.Fatalf("Expected 1 rule but got %d", len(rules))
+	}
+
+	rule := rules[0]
+	if *rule.Name != "from-json" || *rule.Priority != 200 || rule.Direction != network.SecurityRuleDirectionOutbound {
+		t.Fatalf("Expected the rule from `security_rules_json` but got %q", *rule.Name)
+	}
+	if rule.DestinationPortRanges == nil || !reflect.DeepEqual(*rule.DestinationPortRanges, []string{"80", "443"}) {
+		t.Fatalf("Expected the destination port ranges to be split but got %+v", rule.DestinationPortRanges)
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_noSecurityRules(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceArmNetworkSecurityGroup().Schema, map[string]interface{}{
+		"name":                "acctestnsg",
+		"resource_group_name": "acctestrg",
+		"location":            "westeurope",
+		"security_rule":       []interface{}{},
+	})
+
+	// the rules are always sent to the API, so an empty list must be sent rather than null
+	rules, err := expandAzureRmSecurityRules(d, false)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %+v", err)
+	}
+	if rules == nil || len(rules) != 0 {
+		t.Fatalf("Expected an empty list of Security Rules but got %+v", rules)
+	}
+
+	for _, input := range []*[]network.SecurityRule{nil, {}} {
+		flattened := flattenNetworkSecurityRules(input, "")
+		if flattened == nil || len(flattened) != 0 {
+			t.Fatalf("Expected no flattened Security Rules for %+v but got %+v", input, flattened)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_waitForAvailableReturnsID(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestrg/providers/Microsoft.Network/networkSecurityGroups/acctestnsg"
+
+	sender := testAzureResponseSender(http.StatusOK, fmt.Sprintf(`{"id": %q, "name": "acctestnsg", "properties": {"provisioningState": "Succeeded"}}`, id))
+	client := network.NewSecurityGroupsClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	client.Sender = sender
+
+	sg, err := waitForNetworkSecurityGroupToBeAvailable(client, "acctestrg", "acctestnsg", time.Minute)
+	if err != nil {
+		t.Fatalf("Expected no error but got: %+v", err)
+	}
+
+	if sg.ID == nil || *sg.ID != id {
+		t.Fatalf("Expected the ID to be %q but got %+v", id, sg.ID)
+	}
+
+	// the ID comes from the poll, rather than an additional request
+	if len(sender.Requests) != 1 {
+		t.Fatalf("Expected 1 request but got %d", len(sender.Requests))
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_readSkipsFlowLogStatusByDefault(t *testing.T) {
+	id := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestrg/providers/Microsoft.Network/networkSecurityGroups/acctestnsg"
+
+	client := network.NewSecurityGroupsClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	client.Sender = testAzureResponseSender(http.StatusOK, fmt.Sprintf(`{"id": %q, "name": "acctestnsg", "location": "westeurope", "properties": {"provisioningState": "Succeeded", "securityRules": []}}`, id))
+
+	watcherClient := network.NewWatchersClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+	watcherClient.Sender = autorest.SenderFunc(func(r *http.Request) (*http.Response, error) {
+		t.Fatalf("Expected the Network Watchers not to be queried but got a request to %q", r.URL.Path)
+		return nil, nil
+	})
+
+	meta := &ArmClient{
+		secGroupClient: client,
+		watcherClient:  watcherClient,
+	}
+
+	// a value retrieved previously (e.g. before the lookup was disabled) shouldn't be kept
+	state := &terraform.InstanceState{
+		ID: id,
+		Attributes: map[string]string{
+			"id":                               id,
+			"network_watcher_flow_log_enabled": "true",
+		},
+		Meta: map[string]interface{}{
+			"schema_version": "1",
+		},
+	}
+
+	refreshed, err := resourceArmNetworkSecurityGroup().Refresh(state, meta)
+	if err != nil {
+		t.Fatalf("Error reading the Network Security Group: %+v", err)
+	}
+
+	if actual := refreshed.Attributes["network_watcher_flow_log_enabled"]; actual != "false" {
+		t.Fatalf("Expected `network_watcher_flow_log_enabled` to be false but got %q", actual)
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_closelySpacedPriorities(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Rules    []interface{}
+		Expected int
+	}{
+		{
+			Name: "Spaced",
+			Rules: []interface{}{
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule1", "priority": 100}),
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule2", "priority": 200}),
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule3", "priority": 300}),
+			},
+			Expected: 0,
+		},
+		{
+			Name: "Close",
+			Rules: []interface{}{
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule1", "priority": 100}),
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule2", "priority": 110}),
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule3", "priority": 300}),
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule4", "priority": 399}),
+			},
+			Expected: 2,
+		},
+		{
+			Name: "Close But Different Directions",
+			Rules: []interface{}{
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule1", "priority": 100, "direction": "Inbound"}),
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule2", "priority": 110, "direction": "Outbound"}),
+			},
+			Expected: 0,
+		},
+		{
+			Name: "Duplicate",
+			Rules: []interface{}{
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule1", "priority": 100}),
+				testNetworkSecurityGroupRule(map[string]interface{}{"name": "rule2", "priority": 100}),
+			},
+			Expected: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		if actual := closelySpacedSecurityRulePriorities(tc.Rules); len(actual) != tc.Expected {
+			t.Fatalf("Expected %d warnings for %q but got %d: %+v", tc.Expected, tc.Name, len(actual), actual)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_removeAllSecurityRules(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.Network/networkSecurityGroups/acctestnsg",
+		Attributes: map[string]string{
+			"name":                "acctestnsg",
+			"location":            "westeurope",
+			"resource_group_name": "acctestRG",
+		},
+	}
+
+	rule := testNetworkSecurityGroupRule(map[string]interface{}{
+		"name":                       "allow-ssh",
+		"destination_port_range":     "22",
+		"source_address_prefix":      "*",
+		"destination_address_prefix": "*",
+	})
+	writer := schema.MapFieldWriter{Schema: resourceArmNetworkSecurityGroup().Schema}
+	if err := writer.WriteField([]string{"security_rule"}, schema.NewSet(resourceArmNetworkSecurityGroupRuleHash, []interface{}{rule})); err != nil {
+		t.Fatalf("Error writing the Security Rules: %+v", err)
+	}
+	for k, v := range writer.Map() {
+		state.Attributes[k] = v
+	}
+
+	cases := []struct {
+		Name          string
+		SecurityRules interface{}
+		ExpectRemoved bool
+	}{
+		{
+			// omitting `security_rule` leaves the existing rules in place, since they may be managed elsewhere
+			Name:          "Omitted",
+			SecurityRules: nil,
+			ExpectRemoved: false,
+		},
+		{
+			Name:          "Empty",
+			SecurityRules: []interface{}{},
+			ExpectRemoved: true,
+		},
+	}
+
+	for _, tc := range cases {
+		raw := map[string]interface{}{
+			"name":                "acctestnsg",
+			"location":            "westeurope",
+			"resource_group_name": "acctestRG",
+		}
+		if tc.SecurityRules != nil {
+			raw["security_rule"] = tc.SecurityRules
+		}
+
+		rawConfig, err := config.NewRawConfig(raw)
+		if err != nil {
+			t.Fatalf("Error building the configuration for %q: %+v", tc.Name, err)
+		}
+
+		diff, err := resourceArmNetworkSecurityGroup().Diff(state, terraform.NewResourceConfig(rawConfig))
+		if err != nil {
+			t.Fatalf("Error diffing %q: %+v", tc.Name, err)
+		}
+
+		var count *terraform.ResourceAttrDiff
+		if diff != nil {
+			count = diff.Attributes["security_rule.#"]
+		}
+
+		removed := count != nil && count.Old == "1" && count.New == "0"
+		if removed != tc.ExpectRemoved {
+			t.Fatalf("Expected the Security Rules to be removed for %q to be %t but got the diff %+v", tc.Name, tc.ExpectRemoved, diff)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_createOrUpdateOnlyRetriesConflicts(t *testing.T) {
+	// a 429 isn't covered here since the SDK retries it itself until it succeeds
+	cases := []struct {
+		Name       string
+		StatusCode int
+		// the SDK itself retries a 503 once before returning it
+		RequestsPerAttempt int
+		ExpectedRetried    bool
+	}{
+		{Name: "Conflict", StatusCode: http.StatusConflict, RequestsPerAttempt: 1, ExpectedRetried: true},
+		{Name: "Service Unavailable", StatusCode: http.StatusServiceUnavailable, RequestsPerAttempt: 2, ExpectedRetried: false},
+		{Name: "Bad Request", StatusCode: http.StatusBadRequest, RequestsPerAttempt: 1, ExpectedRetried: false},
+	}
+
+	for _, tc := range cases {
+		sender := testAzureResponseSender(tc.StatusCode, `{"error":{"code":"Failed","message":"The request failed."}}`)
+		client := network.NewSecurityGroupsClientWithBaseURI("https://management.azure.com", "00000000-0000-0000-0000-000000000000")
+		client.Sender = sender
+		client.RetryAttempts = 1
+		client.RetryDuration = 0
+		client.SkipResourceProviderRegistration = true
+
+		err := createOrUpdateNetworkSecurityGroup(client, "acctestRG", "acctestnsg", network.SecurityGroup{}, 2*time.Second)
+		if err == nil {
+			t.Fatalf("Expected an error for %q but didn't get one", tc.Name)
+		}
+
+		if retried := len(sender.Requests) > tc.RequestsPerAttempt; retried != tc.ExpectedRetried {
+			t.Fatalf("Expected %q to be retried to be %t but got %d requests: %+v", tc.Name, tc.ExpectedRetried, len(sender.Requests), err)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_wasInUse(t *testing.T) {
+	cases := []struct {
+		Name       string
+		StatusCode int
+		Body       string
+		Expected   bool
+	}{
+		{
+			Name:       "In Use",
+			StatusCode: http.StatusBadRequest,
+			Body:       `{"error":{"code":"InUseNetworkSecurityGroup","message":"Network security group /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.Network/networkSecurityGroups/acctestnsg cannot be deleted because it is in use by the following resources: /subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/acctestRG/providers/Microsoft.Network/virtualNetworks/acctestvn/subnets/acctestsubnet.","details":[]}}`,
+			Expected:   true,
+		},
+		{
+			Name:       "Conflict",
+			StatusCode: http.StatusConflict,
+			Body:       `{"error":{"code":"AnotherOperationInProgress","message":"Another operation on this or dependent resource is in progress."}}`,
+			Expected:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := testAzureRequestError(tc.StatusCode, tc.Body)
+		if actual := wasNetworkSecurityGroupInUse(err); actual != tc.Expected {
+			t.Fatalf("Expected %q to return %t but got %t", tc.Name, tc.Expected, actual)
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_changedRuleNames(t *testing.T) {
+	ssh := testNetworkSecurityGroupRule(map[string]interface{}{
+		"name":                   "ssh",
+		"destination_port_range": "22",
+		"priority":               100,
+	})
+	sshUpdated := testNetworkSecurityGroupRule(map[string]interface{}{
+		"name":                   "ssh",
+		"destination_port_range": "2222",
+		"priority":               100,
+	})
+	https := testNetworkSecurityGroupRule(map[string]interface{}{
+		"name":                   "https",
+		"destination_port_range": "443",
+		"priority":               110,
+	})
+	http := testNetworkSecurityGroupRule(map[string]interface{}{
+		"name":                   "http",
+		"destination_port_range": "80",
+		"priority":               120,
+	})
+
+	old := schema.NewSet(resourceArmNetworkSecurityGroupRuleHash, []interface{}{ssh, https, http})
+	new := schema.NewSet(resourceArmNetworkSecurityGroupRuleHash, []interface{}{sshUpdated, https})
+
+	actual := changedNetworkSecurityRuleNames(old, new)
+	expected := []string{"http", "ssh"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected only the modified and removed rules %+v to have changed but got %+v", expected, actual)
+	}
+
+	if unchanged := changedNetworkSecurityRuleNames(old, old); len(unchanged) != 0 {
+		t.Fatalf("Expected no rules to have changed but got %+v", unchanged)
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_maxSecurityRules(t *testing.T) {
+	cases := []struct {
+		Count    int
+		ErrCount int
+	}{
+		{Count: 1, ErrCount: 0},
+		{Count: networkSecurityGroupMaxSecurityRules, ErrCount: 0},
+		{Count: networkSecurityGroupMaxSecurityRules + 1, ErrCount: 1},
+	}
+
+	for _, tc := range cases {
+		rules := make([]interface{}, 0)
+		for i := 0; i < tc.Count; i++ {
+			rules = append(rules, map[string]interface{}{
+				"name":                       fmt.Sprintf("rule%d", i),
+				"protocol":                   "Tcp",
+				"source_port_range":          "*",
+				"destination_port_range":     "*",
+				"source_address_prefix":      "*",
+				"destination_address_prefix": "*",
+				"access":                     "Allow",
+				"priority":                   100 + (i % 3996),
+				"direction":                  "Inbound",
+			})
+		}
+
+		config := terraform.NewResourceConfig(nil)
+		config.Raw = map[string]interface{}{
+			"name":                "acctestnsg",
+			"location":            "westeurope",
+			"resource_group_name": "acctestRG",
+			"security_rule":       rules,
+		}
+		config.Config = config.Raw
+
+		_, errors := resourceArmNetworkSecurityGroup().Validate(config)
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected %d errors for %d Security Rules but got %d: %+v", tc.ErrCount, tc.Count, len(errors), errors)
+		}
+
+		if tc.ErrCount > 0 && !strings.Contains(errors[0].Error(), fmt.Sprintf("%d", networkSecurityGroupMaxSecurityRules)) {
+			t.Fatalf("Expected the error to name the limit of %d Security Rules: %+v", networkSecurityGroupMaxSecurityRules, errors[0])
+		}
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_requestError(t *testing.T) {
+	cause := fmt.Errorf("bad request")
+
+	withRequestId := &http.Response{
+		Header: http.Header{"X-Ms-Request-Id": []string{"00000000-0000-0000-0000-000000000000"}},
+	}
+	err := networkSecurityGroupRequestError(withRequestId, cause, "Error deleting Network Security Group %q", "nsg1")
+	expected := `Error deleting Network Security Group "nsg1" (Request ID "00000000-0000-0000-0000-000000000000"): bad request`
+	if err.Error() != expected {
+		t.Fatalf("Expected the error to be %q but got %q", expected, err.Error())
+	}
+
+	err = networkSecurityGroupRequestError(nil, cause, "Error deleting Network Security Group %q", "nsg1")
+	expected = `Error deleting Network Security Group "nsg1": bad request`
+	if err.Error() != expected {
+		t.Fatalf("Expected the error to be %q but got %q", expected, err.Error())
+	}
+}
+
+func TestResourceAzureRMNetworkSecurityGroup_findNetworkWatcherForLocation(t *testing.T) {
+	watchers := []network.Watcher{
+		{
+			Location: utils.String("westus"),
+		},
+		{
+			ID:       utils.String("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/NetworkWatcherRG/providers/Microsoft.Network/networkWatchers/NetworkWatcher_westus"),
+			Location: utils.String("westus"),
+		},
+		{
+			ID:       utils.String("/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/NetworkWatcherRG/providers/Microsoft.Network/networkWatchers/NetworkWatcher_westeurope"),
+			Location: utils.String("westeurope"),
+		},
+	}
+
+	cases := []struct {
+		Location string
+		Expected string
+	}{
+		{
+			Location: "westus",
+			Expected: "NetworkWatcher_westus",
+		},
+		{
+			Location: "West Europe",
+			Expected: "NetworkWatcher_westeurope",
+		},
+		{
+			Location: "eastus",
+			Expected: "",
+		},
+	}
+
+	for _, tc := range cases {
+		watcher := findNetworkWatcherForLocation(watchers, tc.Location)
+
+		actual := ""
+		if watcher != nil {
+			id, err := parseAzureResourceID(*watcher.ID)
+			if err != nil {
+				t.Fatalf("Error parsing the Network Watcher ID: %+v", err)
+			}
+			actual = id.Path["networkWatchers"]
+		}
+
+		if actual != tc.Expected {
+			t.Fatalf("Expected the Network Watcher for %q to be %q but got %q", tc.Location, tc.Expected, actual)
+		}
+	}
 }
 
-func TestAccAzureRMNetworkSecurityGroup_disappears(t *testing.T) {
-	resourceName := "azurerm_network_security_group.test"
-	rInt := acctest.RandInt()
-	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccAzureRMNetworkSecurityGroup_basic(rInt, testLocation()),
-				Check: resource.ComposeTestCheckFunc(
-					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
-					testCheckAzureRMNetworkSecurityGroupDisappears(resourceName),
-				),
-				ExpectNonEmptyPlan: true,
+func TestResourceAzureRMNetworkSecurityGroupRule_allowsInboundInternetToAnyPort(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Rule     map[string]interface{}
+		Expected bool
+	}{
+		{
+			Name: "Any Source to Any Port",
+			Rule: map[string]interface{}{
+				"source_address_prefix": "*",
 			},
+			Expected: true,
 		},
-	})
-}
-
-func TestAccAzureRMNetworkSecurityGroup_withTags(t *testing.T) {
-	resourceName := "azurerm_network_security_group.test"
-	rInt := acctest.RandInt()
-	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccAzureRMNetworkSecurityGroup_withTags(rInt, testLocation()),
-				Check: resource.ComposeTestCheckFunc(
-					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
-					resource.TestCheckResourceAttr(resourceName, "tags.%", "2"),
-					resource.TestCheckResourceAttr(resourceName, "tags.environment", "Production"),
-					resource.TestCheckResourceAttr(resourceName, "tags.cost_center", "MSFT"),
-				),
+		{
+			Name: "Internet to Any Port",
+			Rule: map[string]interface{}{
+				"source_address_prefix": "internet",
 			},
-
-			{
-				Config: testAccAzureRMNetworkSecurityGroup_withTagsUpdate(rInt, testLocation()),
-				Check: resource.ComposeTestCheckFunc(
-					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
-					resource.TestCheckResourceAttr(resourceName, "tags.%", "1"),
-					resource.TestCheckResourceAttr(resourceName, "tags.environment", "staging"),
-				),
+			Expected: true,
+		},
+		{
+			Name: "Internet in Source Address Prefixes",
+			Rule: map[string]interface{}{
+				"source_address_prefixes": []interface{}{"10.0.0.0/24", "Internet"},
 			},
+			Expected: true,
 		},
-	})
+		{
+			Name: "Internet to a Single Port",
+			Rule: map[string]interface{}{
+				"source_address_prefix":  "Internet",
+				"destination_port_range": "443",
+			},
+			Expected: false,
+		},
+		{
+			Name: "Any Port in Destination Port Ranges",
+			Rule: map[string]interface{}{
+				"source_address_prefix":   "Internet",
+				"destination_port_range":  "",
+				"destination_port_ranges": []interface{}{"443", "*"},
+			},
+			Expected: true,
+		},
+		{
+			Name: "Any Port in a Comma-Separated Destination Port Range",
+			Rule: map[string]interface{}{
+				"source_address_prefix":  "Internet",
+				"destination_port_range": "443, *",
+			},
+			Expected: true,
+		},
+		{
+			Name: "Internet to Several Ports",
+			Rule: map[string]interface{}{
+				"source_address_prefix":   "Internet",
+				"destination_port_range":  "",
+				"destination_port_ranges": []interface{}{"80", "443"},
+			},
+			Expected: false,
+		},
+		{
+			Name: "Virtual Network to Any Port",
+			Rule: map[string]interface{}{
+				"source_address_prefix": "VirtualNetwork",
+			},
+			Expected: false,
+		},
+		{
+			Name: "Deny Internet to Any Port",
+			Rule: map[string]interface{}{
+				"source_address_prefix": "*",
+				"access":                "Deny",
+			},
+			Expected: false,
+		},
+		{
+			Name: "Outbound to the Internet",
+			Rule: map[string]interface{}{
+				"source_address_prefix": "*",
+				"direction":             "Outbound",
+			},
+			Expected: false,
+		},
+	}
+
+	for _, tc := range cases {
+		rule := testNetworkSecurityGroupRule(tc.Rule)
+		if actual := securityRuleAllowsInboundInternetToAnyPort(rule); actual != tc.Expected {
+			t.Fatalf("Expected %q to return %t but got %t", tc.Name, tc.Expected, actual)
+		}
+	}
 }
 
-func TestAccAzureRMNetworkSecurityGroup_addingExtraRules(t *testing.T) {
-	resourceName := "azurerm_network_security_group.test"
-	rInt := acctest.RandInt()
-	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testCheckAzureRMNetworkSecurityGroupDestroy,
-		Steps: []resource.TestStep{
-			{
-				Config: testAccAzureRMNetworkSecurityGroup_singleRule(rInt, testLocation()),
-				Check: resource.ComposeTestCheckFunc(
-					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
-					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "1"),
-				),
-			},
+func TestResourceAzureRMNetworkSecurityGroup_securityRulesJSONWarnings(t *testing.T) {
+	value := `[
+  {"name": "open", "priority": 100, "direction": "Inbound", "access": "Allow", "protocol": "Tcp", "source_port_range": "*", "destination_port_range": "*", "source_address_prefix": "Internet", "destination_address_prefix": "*"},
+  {"name": "overlap", "priority": 110, "direction": "Inbound", "access": "Allow", "protocol": "Tcp", "source_port_range": "*", "destination_port_range": "22", "source_address_prefixes": ["10.0.0.0/16", "10.0.1.0/24"], "destination_address_prefix": "*"},
+  {"name": "fine", "priority": 120, "direction": "Inbound", "access": "Allow", "protocol": "Tcp", "source_port_range": "*", "destination_port_range": "443", "source_address_prefix": "VirtualNetwork", "destination_address_prefix": "*"}
+]`
+	overlap := `security_rules_json[1]: The source_address_prefixes "10.0.0.0/16" and "10.0.1.0/24" of Security Rule "overlap" overlap`
+	open := `security_rules_json[0]: Security Rule "open" allows inbound traffic from the Internet to any port`
 
-			{
-				Config: testAccAzureRMNetworkSecurityGroup_anotherRule(rInt, testLocation()),
-				Check: resource.ComposeTestCheckFunc(
-					testCheckAzureRMNetworkSecurityGroupExists(resourceName),
-					resource.TestCheckResourceAttr(resourceName, "security_rule.#", "2"),
-				),
-			},
+	cases := []struct {
+		Enabled  string
+		Expected []string
+	}{
+		{
+			Enabled:  "",
+			Expected: []string{overlap},
 		},
-	})
+		{
+			Enabled:  "true",
+			Expected: []string{open, overlap},
+		},
+	}
+
+	defer os.Setenv(openSecurityRuleWarningsEnvVar, os.Getenv(openSecurityRuleWarningsEnvVar))
+	for _, tc := range cases {
+		os.Setenv(openSecurityRuleWarningsEnvVar, tc.Enabled)
+
+		ws, errors := validateNetworkSecurityRulesJSON(value, "security_rules_json")
+		if len(errors) > 0 {
+			t.Fatalf("Expected no errors but got: %+v", errors)
+		}
+		if len(ws) != len(tc.Expected) {
+			t.Fatalf("Expected %d warnings when %s is %q but got %d: %+v", len(tc.Expected), openSecurityRuleWarningsEnvVar, tc.Enabled, len(ws), ws)
+		}
+		for i, warning := range tc.Expected {
+			if ws[i] != warning {
+				t.Fatalf("Expected warning %d to be %q but got %q", i, warning, ws[i])
+			}
+		}
+	}
+}
+
+// testNetworkSecurityGroupRule builds a security rule map in the shape Terraform
+// provides it to expandAzureRmSecurityRules, overlaying the supplied values
+func testNetworkSecurityGroupRule(values map[string]interface{}) map[string]interface{} {
+	rule := map[string]interface{}{
+		"name":                       "rule1",
+		"description":                "",
+		"protocol":                   "Tcp",
+		"source_port_range":          "*",
+		"destination_port_range":     "*",
+		"source_address_prefix":      "",
+		"destination_address_prefix": "",
+		"access":                     "Allow",
+		"priority":                   100,
+		"direction":                  "Inbound",
+	}
+
+	for _, key := range []string{
+		"source_port_ranges",
+		"destination_port_ranges",
+		"source_address_prefixes",
+		"destination_address_prefixes",
+		"source_application_security_group_ids",
+		"destination_application_security_group_ids",
+	} {
+		rule[key] = schema.NewSet(schema.HashString, []interface{}{})
+	}
+
+	for k, v := range values {
+		if list, ok := v.([]interface{}); ok {
+			rule[k] = schema.NewSet(schema.HashString, list)
+			continue
+		}
+		rule[k] = v
+	}
+
+	return rule
 }
 
 func testCheckAzureRMNetworkSecurityGroupExists(name string) resource.TestCheckFunc {
@@ -176,6 +1503,81 @@ func testCheckAzureRMNetworkSecurityGroupExists(name string) resource.TestCheckF
 	}
 }
 
+func testCheckAzureRMNetworkSecurityGroupRuleDestinationPortRange(name string, ruleName string, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %q", name)
+		}
+
+		sgName := rs.Primary.Attributes["name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+
+		client := testAccProvider.Meta().(*ArmClient).secGroupClient
+		resp, err := client.Get(resourceGroup, sgName, "")
+		if err != nil {
+			return fmt.Errorf("Bad: Get on secGroupClient: %+v", err)
+		}
+
+		if props := resp.SecurityGroupPropertiesFormat; props != nil && props.SecurityRules != nil {
+			for _, rule := range *props.SecurityRules {
+				if rule.Name == nil || *rule.Name != ruleName {
+					continue
+				}
+
+				if rule.SecurityRulePropertiesFormat == nil || rule.DestinationPortRange == nil {
+					return fmt.Errorf("Bad: Security Rule %q has no Destination Port Range", ruleName)
+				}
+
+				if *rule.DestinationPortRange != expected {
+					return fmt.Errorf("Bad: expected the Destination Port Range of Security Rule %q to be %q but got %q", ruleName, expected, *rule.DestinationPortRange)
+				}
+
+				return nil
+			}
+		}
+
+		return fmt.Errorf("Bad: Security Rule %q was not found in Network Security Group %q (resource group: %q)", ruleName, sgName, resourceGroup)
+	}
+}
+
+func testCheckAzureRMNetworkSecurityGroupAugmentedRule(name string, sourcePortRanges, destinationPortRanges, sourceAddressPrefixes, destinationAddressPrefixes int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("Not found: %q", name)
+		}
+
+		sgName := rs.Primary.Attributes["name"]
+		resourceGroup := rs.Primary.Attributes["resource_group_name"]
+
+		client := testAccProvider.Meta().(*ArmClient).secGroupClient
+		resp, err := client.Get(resourceGroup, sgName, "")
+		if err != nil {
+			return fmt.Errorf("Bad: Get on secGroupClient: %+v", err)
+		}
+
+		if resp.SecurityGroupPropertiesFormat == nil || resp.SecurityRules == nil || len(*resp.SecurityRules) != 1 {
+			return fmt.Errorf("Bad: expected Network Security Group %q to have a single Security Rule", sgName)
+		}
+
+		props := (*resp.SecurityRules)[0].SecurityRulePropertiesFormat
+		checks := map[string][]int{
+			"source_port_ranges":           {sourcePortRanges, len(*props.SourcePortRanges)},
+			"destination_port_ranges":      {destinationPortRanges, len(*props.DestinationPortRanges)},
+			"source_address_prefixes":      {sourceAddressPrefixes, len(*props.SourceAddressPrefixes)},
+			"destination_address_prefixes": {destinationAddressPrefixes, len(*props.DestinationAddressPrefixes)},
+		}
+		for field, v := range checks {
+			if v[0] != v[1] {
+				return fmt.Errorf("Bad: expected %d %s but got %d", v[0], field, v[1])
+			}
+		}
+
+		return nil
+	}
+}
+
 func testCheckAzureRMNetworkSecurityGroupDisappears(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 
@@ -245,6 +1647,79 @@ resource "azurerm_network_security_group" "test" {
 `, rInt, location)
 }
 
+func testAccAzureRMNetworkSecurityGroup_noRules(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_network_security_group" "test" {
+  name                = "acceptanceTestSecurityGroup1"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+  security_rule       = []
+}
+`, rInt, location)
+}
+
+func testAccAzureRMNetworkSecurityGroup_securityRulesJson(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_network_security_group" "test" {
+  name                = "acceptanceTestSecurityGroup1"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+
+  security_rules_json = <<JSON
+[
+  {
+    "name": "ssh",
+    "priority": 100,
+    "direction": "Inbound",
+    "access": "Allow",
+    "protocol": "Tcp",
+    "source_port_range": "*",
+    "destination_port_range": "22",
+    "source_address_prefix": "*",
+    "destination_address_prefix": "*"
+  },
+  {
+    "name": "web",
+    "priority": 110,
+    "direction": "Inbound",
+    "access": "Allow",
+    "protocol": "Tcp",
+    "source_port_range": "*",
+    "destination_port_ranges": ["80", "443"],
+    "source_address_prefix": "*",
+    "destination_address_prefix": "*"
+  }
+]
+JSON
+}
+`, rInt, location)
+}
+
+func testAccAzureRMNetworkSecurityGroup_named(rInt int, location string, name string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_network_security_group" "test" {
+  name                = "%s"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+}
+`, rInt, location, name)
+}
+
 func testAccAzureRMNetworkSecurityGroup_singleRule(rInt int, location string) string {
 	return fmt.Sprintf(`
 resource "azurerm_resource_group" "test" {
@@ -273,6 +1748,45 @@ resource "azurerm_network_security_group" "test" {
 `, rInt, location)
 }
 
+func testAccAzureRMNetworkSecurityGroup_twoRules(rInt int, location string, sshPort string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_network_security_group" "test" {
+  name                = "acceptanceTestSecurityGroup1"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+
+  security_rule {
+    name                       = "ssh"
+    priority                   = 100
+    direction                  = "Inbound"
+    access                     = "Allow"
+    protocol                   = "Tcp"
+    source_port_range          = "*"
+    destination_port_range     = "%s"
+    source_address_prefix      = "VirtualNetwork"
+    destination_address_prefix = "*"
+  }
+
+  security_rule {
+    name                       = "https"
+    priority                   = 110
+    direction                  = "Inbound"
+    access                     = "Allow"
+    protocol                   = "Tcp"
+    source_port_range          = "*"
+    destination_port_range     = "443"
+    source_address_prefix      = "*"
+    destination_address_prefix = "*"
+  }
+}
+`, rInt, location, sshPort)
+}
+
 func testAccAzureRMNetworkSecurityGroup_anotherRule(rInt int, location string) string {
 	return fmt.Sprintf(`
 resource "azurerm_resource_group" "test" {
@@ -375,3 +1889,30 @@ resource "azurerm_network_security_group" "test" {
 
 `, rInt, location)
 }
+
+func testAccAzureRMNetworkSecurityGroup_augmented(rInt int, location string) string {
+	return fmt.Sprintf(`
+resource "azurerm_resource_group" "test" {
+  name     = "acctestRG-%d"
+  location = "%s"
+}
+
+resource "azurerm_network_security_group" "test" {
+  name                = "acceptanceTestSecurityGroup1"
+  location            = "${azurerm_resource_group.test.location}"
+  resource_group_name = "${azurerm_resource_group.test.name}"
+
+  security_rule {
+    name                         = "test123"
+    priority                     = 100
+    direction                    = "Inbound"
+    access                       = "Allow"
+    protocol                     = "Tcp"
+    source_port_ranges           = ["10000-40000"]
+    destination_port_ranges      = ["80", "443"]
+    source_address_prefixes      = ["10.0.0.0/24", "10.0.1.0/24"]
+    destination_address_prefixes = ["172.16.0.0/24", "172.16.1.0/24"]
+  }
+}
+`, rInt, location)
+}
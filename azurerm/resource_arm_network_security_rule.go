@@ -54,13 +54,17 @@ func resourceArmNetworkSecurityRule() *schema.Resource {
 			"source_port_range": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				ValidateFunc:  validatePortRangeListOrStar,
 				ConflictsWith: []string{"source_port_ranges"},
 			},
 
 			"source_port_ranges": {
-				Type:          schema.TypeSet,
-				Optional:      true,
-				Elem:          &schema.Schema{Type: schema.TypeString},
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validatePortRangeOrStar,
+				},
 				Set:           schema.HashString,
 				ConflictsWith: []string{"source_port_range"},
 			},
@@ -68,13 +72,17 @@ func resourceArmNetworkSecurityRule() *schema.Resource {
 			"destination_port_range": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				ValidateFunc:  validatePortRangeListOrStar,
 				ConflictsWith: []string{"destination_port_ranges"},
 			},
 
 			"destination_port_ranges": {
-				Type:          schema.TypeSet,
-				Optional:      true,
-				Elem:          &schema.Schema{Type: schema.TypeString},
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validatePortRangeOrStar,
+				},
 				Set:           schema.HashString,
 				ConflictsWith: []string{"destination_port_range"},
 			},
@@ -82,13 +90,17 @@ func resourceArmNetworkSecurityRule() *schema.Resource {
 			"source_address_prefix": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				ValidateFunc:  validateNetworkSecurityRuleAddressPrefix,
 				ConflictsWith: []string{"source_address_prefixes"},
 			},
 
 			"source_address_prefixes": {
-				Type:          schema.TypeSet,
-				Optional:      true,
-				Elem:          &schema.Schema{Type: schema.TypeString},
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateNetworkSecurityRuleAddressPrefix,
+				},
 				Set:           schema.HashString,
 				ConflictsWith: []string{"source_address_prefix"},
 			},
@@ -96,13 +108,17 @@ func resourceArmNetworkSecurityRule() *schema.Resource {
 			"destination_address_prefix": {
 				Type:          schema.TypeString,
 				Optional:      true,
+				ValidateFunc:  validateNetworkSecurityRuleAddressPrefix,
 				ConflictsWith: []string{"destination_address_prefixes"},
 			},
 
 			"destination_address_prefixes": {
-				Type:          schema.TypeSet,
-				Optional:      true,
-				Elem:          &schema.Schema{Type: schema.TypeString},
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateNetworkSecurityRuleAddressPrefix,
+				},
 				Set:           schema.HashString,
 				ConflictsWith: []string{"destination_address_prefix"},
 			},
@@ -120,7 +136,7 @@ func resourceArmNetworkSecurityRule() *schema.Resource {
 			"priority": {
 				Type:         schema.TypeInt,
 				Required:     true,
-				ValidateFunc: validation.IntBetween(100, 4096),
+				ValidateFunc: validation.IntBetween(networkSecurityRuleMinPriority, networkSecurityRuleMaxPriority),
 			},
 
 			"direction": {
@@ -143,8 +159,9 @@ func resourceArmNetworkSecurityRuleCreate(d *schema.ResourceData, meta interface
 	nsgName := d.Get("network_security_group_name").(string)
 	resGroup := d.Get("resource_group_name").(string)
 
-	source_port_range := d.Get("source_port_range").(string)
-	destination_port_range := d.Get("destination_port_range").(string)
+	// a comma-separated list of ports is sent to Azure as the `*_port_ranges`
+	sourcePortRange, sourcePortRanges := expandNetworkSecurityRulePortRanges(d.Get("source_port_range").(string))
+	destinationPortRange, destinationPortRanges := expandNetworkSecurityRulePortRanges(d.Get("destination_port_range").(string))
 	source_address_prefix := d.Get("source_address_prefix").(string)
 	destination_address_prefix := d.Get("destination_address_prefix").(string)
 	priority := int32(d.Get("priority").(int))
@@ -158,8 +175,10 @@ func resourceArmNetworkSecurityRuleCreate(d *schema.ResourceData, meta interface
 	rule := network.SecurityRule{
 		Name: &name,
 		SecurityRulePropertiesFormat: &network.SecurityRulePropertiesFormat{
-			SourcePortRange:          &source_port_range,
-			DestinationPortRange:     &destination_port_range,
+			SourcePortRange:          sourcePortRange,
+			SourcePortRanges:         sourcePortRanges,
+			DestinationPortRange:     destinationPortRange,
+			DestinationPortRanges:    destinationPortRanges,
 			SourceAddressPrefix:      &source_address_prefix,
 			DestinationAddressPrefix: &destination_address_prefix,
 			Priority:                 &priority,
@@ -259,17 +278,21 @@ func resourceArmNetworkSecurityRuleRead(d *schema.ResourceData, meta interface{}
 	if props := resp.SecurityRulePropertiesFormat; props != nil {
 		d.Set("access", string(props.Access))
 		d.Set("destination_address_prefix", props.DestinationAddressPrefix)
-		d.Set("destination_port_range", props.DestinationPortRange)
 		d.Set("direction", string(props.Direction))
 		d.Set("description", props.Description)
 		d.Set("priority", int(*props.Priority))
 		d.Set("protocol", string(props.Protocol))
 		d.Set("source_address_prefix", props.SourceAddressPrefix)
-		d.Set("source_port_range", props.SourcePortRange)
 		d.Set("source_address_prefixes", props.SourceAddressPrefixes)
 		d.Set("destination_address_prefixes", props.DestinationAddressPrefixes)
-		d.Set("source_port_ranges", props.SourcePortRanges)
-		d.Set("destination_port_ranges", props.DestinationPortRanges)
+
+		sourcePortRange, sourcePortRanges := flattenNetworkSecurityRulePortRanges(d.Get("source_port_range").(string), props.SourcePortRange, props.SourcePortRanges)
+		d.Set("source_port_range", sourcePortRange)
+		d.Set("source_port_ranges", sourcePortRanges)
+
+		destinationPortRange, destinationPortRanges := flattenNetworkSecurityRulePortRanges(d.Get("destination_port_range").(string), props.DestinationPortRange, props.DestinationPortRanges)
+		d.Set("destination_port_range", destinationPortRange)
+		d.Set("destination_port_ranges", destinationPortRanges)
 	}
 
 	return nil
@@ -115,8 +115,10 @@ func resourceArmVirtualNetworkCreate(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
-	azureRMLockMultipleByName(&networkSecurityGroupNames, networkSecurityGroupResourceName)
-	defer azureRMUnlockMultipleByName(&networkSecurityGroupNames, networkSecurityGroupResourceName)
+	for _, nsgName := range networkSecurityGroupNames {
+		azureRMLockByName(nsgName, networkSecurityGroupResourceName)
+		defer azureRMUnlockByName(nsgName, networkSecurityGroupResourceName)
+	}
 
 	_, error := vnetClient.CreateOrUpdate(resGroup, name, vnet, make(chan struct{}))
 	err := <-error
@@ -209,8 +211,10 @@ func resourceArmVirtualNetworkDelete(d *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("[ERROR] Error parsing Network Security Group ID's: %+v", err)
 	}
 
-	azureRMLockMultipleByName(&nsgNames, virtualNetworkResourceName)
-	defer azureRMUnlockMultipleByName(&nsgNames, virtualNetworkResourceName)
+	for _, nsgName := range nsgNames {
+		azureRMLockByName(nsgName, networkSecurityGroupResourceName)
+		defer azureRMUnlockByName(nsgName, networkSecurityGroupResourceName)
+	}
 
 	_, error := vnetClient.Delete(resGroup, name, make(chan struct{}))
 	err = <-error
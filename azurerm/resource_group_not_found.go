@@ -0,0 +1,23 @@
+package azurerm
+
+import (
+	"fmt"
+)
+
+// resourceGroupNotFoundErrorCode is the error code Azure returns when the Resource Group a resource's
+// being created in doesn't exist
+const resourceGroupNotFoundErrorCode = "ResourceGroupNotFound"
+
+// resourceGroupNotFoundError returns a friendly error when `err` is a `ResourceGroupNotFound` error
+// from the API, otherwise `err` is returned unchanged
+func resourceGroupNotFoundError(resourceGroup string, err error) error {
+	if !wasResourceGroupNotFound(err) {
+		return err
+	}
+
+	return fmt.Errorf("Resource Group %q was not found - it must exist before resources can be created within it, for example by using the `azurerm_resource_group` resource (and referencing its `name`, so that it's created first)", resourceGroup)
+}
+
+func wasResourceGroupNotFound(err error) bool {
+	return azureServiceErrorCode(err) == resourceGroupNotFoundErrorCode
+}
@@ -0,0 +1,68 @@
+package azurerm
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+)
+
+func TestResourceGroupNotFoundError(t *testing.T) {
+	cases := []struct {
+		Name       string
+		StatusCode int
+		Body       string
+		Expected   bool
+	}{
+		{
+			Name:       "Resource Group Not Found",
+			StatusCode: http.StatusNotFound,
+			Body:       `{"error":{"code":"ResourceGroupNotFound","message":"Resource group 'acctestrg' could not be found."}}`,
+			Expected:   true,
+		},
+		{
+			Name:       "Resource Not Found",
+			StatusCode: http.StatusNotFound,
+			Body:       `{"error":{"code":"ResourceNotFound","message":"The Resource 'Microsoft.Network/networkSecurityGroups/acctestnsg' under resource group 'acctestrg' was not found."}}`,
+			Expected:   false,
+		},
+		{
+			Name:       "Bad Request",
+			StatusCode: http.StatusBadRequest,
+			Body:       `{"error":{"code":"InvalidResourceName","message":"Resource name is invalid."}}`,
+			Expected:   false,
+		},
+	}
+
+	for _, tc := range cases {
+		err := testAzureRequestError(tc.StatusCode, tc.Body)
+
+		if actual := wasResourceGroupNotFound(err); actual != tc.Expected {
+			t.Fatalf("Expected %q to return %t but got %t", tc.Name, tc.Expected, actual)
+		}
+
+		actual := resourceGroupNotFoundError("acctestrg", err)
+		if tc.Expected && (!strings.Contains(actual.Error(), `"acctestrg"`) || !strings.Contains(actual.Error(), "azurerm_resource_group")) {
+			t.Fatalf("Expected a friendly error for %q but got: %+v", tc.Name, actual)
+		}
+		if !tc.Expected && actual.Error() != err.Error() {
+			t.Fatalf("Expected the error for %q to be returned unchanged but got: %+v", tc.Name, actual)
+		}
+	}
+}
+
+func TestResourceGroupNotFoundError_otherErrors(t *testing.T) {
+	errs := []error{
+		nil,
+		fmt.Errorf("ResourceGroupNotFound"),
+		autorest.DetailedError{},
+	}
+
+	for _, err := range errs {
+		if wasResourceGroupNotFound(err) {
+			t.Fatalf("Expected %+v not to be a Resource Group Not Found error", err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package azurerm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+)
+
+// testAzureRequest is a request received by a testAzureSender
+type testAzureRequest struct {
+	Method string
+	Path   string
+	Body   string
+}
+
+// testAzureSender is an autorest.Sender which responds to every request with the same Status Code and body,
+// recording each request it receives - so that a resource can be exercised without calling Azure
+type testAzureSender struct {
+	StatusCode int
+	Body       string
+	Requests   []testAzureRequest
+}
+
+func testAzureResponseSender(statusCode int, body string) *testAzureSender {
+	return &testAzureSender{
+		StatusCode: statusCode,
+		Body:       body,
+		Requests:   make([]testAzureRequest, 0),
+	}
+}
+
+func (s *testAzureSender) Do(r *http.Request) (*http.Response, error) {
+	request := testAzureRequest{
+		Method: r.Method,
+		Path:   r.URL.Path,
+	}
+	if r.Body != nil {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		request.Body = string(body)
+	}
+	s.Requests = append(s.Requests, request)
+
+	return testAzureResponse(r, s.StatusCode, s.Body), nil
+}
+
+// testAzureResponse returns a response to the request with the specified Status Code and body,
+// for use by fake senders which need to respond differently depending on the request
+func testAzureResponse(r *http.Request, statusCode int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		Header:     http.Header{},
+		Request:    r,
+	}
+}
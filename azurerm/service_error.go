@@ -0,0 +1,35 @@
+package azurerm
+
+import (
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+// azureServiceErrorCode returns the error code Azure returned (e.g. `ResourceGroupNotFound`) from an error
+// returned by the SDK, or an empty string if there isn't one
+func azureServiceErrorCode(err error) string {
+	switch e := err.(type) {
+	case autorest.DetailedError:
+		return azureServiceErrorCode(e.Original)
+	case *autorest.DetailedError:
+		if e != nil {
+			return azureServiceErrorCode(e.Original)
+		}
+	case azure.RequestError:
+		if e.ServiceError != nil {
+			return e.ServiceError.Code
+		}
+	case *azure.RequestError:
+		if e != nil && e.ServiceError != nil {
+			return e.ServiceError.Code
+		}
+	case azure.ServiceError:
+		return e.Code
+	case *azure.ServiceError:
+		if e != nil {
+			return e.Code
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,65 @@
+package azurerm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+)
+
+func TestAzureServiceErrorCode(t *testing.T) {
+	cases := []struct {
+		Name     string
+		Error    error
+		Expected string
+	}{
+		{
+			Name:     "Request Error",
+			Error:    testAzureRequestError(http.StatusBadRequest, `{"error":{"code":"InUseNetworkSecurityGroup","message":"Network security group acctestnsg cannot be deleted because it is in use."}}`),
+			Expected: "InUseNetworkSecurityGroup",
+		},
+		{
+			Name:     "Service Error",
+			Error:    &azure.ServiceError{Code: "ResourceGroupNotFound"},
+			Expected: "ResourceGroupNotFound",
+		},
+		{
+			Name:     "Detailed Error without a Service Error",
+			Error:    autorest.DetailedError{Original: fmt.Errorf("connection reset")},
+			Expected: "",
+		},
+		{
+			Name:     "Other Error",
+			Error:    fmt.Errorf("InUseNetworkSecurityGroup"),
+			Expected: "",
+		},
+		{
+			Name:     "No Error",
+			Error:    nil,
+			Expected: "",
+		},
+	}
+
+	for _, tc := range cases {
+		if actual := azureServiceErrorCode(tc.Error); actual != tc.Expected {
+			t.Fatalf("Expected the error code for %q to be %q but got %q", tc.Name, tc.Expected, actual)
+		}
+	}
+}
+
+// testAzureRequestError builds the error returned by the SDK for the given response,
+// in the same way as the generated clients do
+func testAzureRequestError(statusCode int, body string) error {
+	resp := &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(body)),
+		Header:     http.Header{},
+	}
+
+	err := autorest.Respond(resp, azure.WithErrorUnlessStatusCode(http.StatusOK))
+	return autorest.NewErrorWithError(err, "network.SecurityGroupsClient", "CreateOrUpdate", resp, "Failure responding to request")
+}
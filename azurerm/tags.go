@@ -3,10 +3,15 @@ package azurerm
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
+// defaultHiddenTagPrefix is the prefix of the tags which Azure adds to some resources (e.g. `hidden-link:`),
+// which can be overridden using the provider's `hidden_tag_prefix`
+const defaultHiddenTagPrefix = "hidden-"
+
 func tagsSchema() *schema.Schema {
 	return &schema.Schema{
 		Type:         schema.TypeMap,
@@ -56,6 +61,18 @@ func validateAzureRMTags(v interface{}, k string) (ws []string, es []error) {
 			es = append(es, fmt.Errorf("the maximum length for a tag key is 512 characters: %q is %d characters", k, len(k)))
 		}
 
+		// these are warnings rather than errors since some resource types accept them, and existing
+		// configurations using them shouldn't start failing to plan
+		for _, prefix := range []string{"azure", "microsoft", "windows"} {
+			if strings.HasPrefix(strings.ToLower(k), prefix) {
+				ws = append(ws, fmt.Sprintf("tag keys starting with the reserved prefix %q may be rejected by Azure: %q", prefix, k))
+			}
+		}
+
+		if strings.ContainsAny(k, `<>%&\?/`) {
+			ws = append(ws, fmt.Sprintf("tag keys containing the characters `<`, `>`, `%%`, `&`, `\\`, `?` or `/` may be rejected by Azure: %q", k))
+		}
+
 		value, err := tagValueToString(v)
 		if err != nil {
 			es = append(es, err)
@@ -79,6 +96,111 @@ func expandTags(tagsMap map[string]interface{}) *map[string]*string {
 	return &output
 }
 
+// mergeDefaultTags returns the provider's `default_tags` combined with the resource's tags,
+// where a tag set on the resource takes precedence over a default tag with the same key
+func mergeDefaultTags(defaults map[string]interface{}, tagsMap map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(defaults)+len(tagsMap))
+
+	for k, v := range defaults {
+		output[k] = v
+	}
+
+	for k, v := range tagsMap {
+		output[k] = v
+	}
+
+	return output
+}
+
+// flattenAndSetTagsWithDefaults sets the tags, excluding any which match the provider's `default_tags` or start with
+// the provider's `hidden_tag_prefix` and weren't set on the resource - so that they don't show as a diff against the configuration
+func flattenAndSetTagsWithDefaults(d *schema.ResourceData, tagsMap *map[string]*string, defaults map[string]interface{}, hiddenTagPrefix string) {
+	remote := make(map[string]*string)
+	if tagsMap != nil {
+		remote = *tagsMap
+	}
+
+	configured := d.Get("tags").(map[string]interface{})
+	tags := removeDefaultTags(remote, configured, defaults)
+	d.Set("tags", removeHiddenTags(tags, configured, hiddenTagPrefix))
+}
+
+// removeDefaultTags removes the default tags whose value matches the resource's. Default tags which have drifted are
+// kept - and those which are missing from the resource (e.g. since they were added to `default_tags` after it was
+// created) are returned with an empty value - so that they show as a diff, and are applied by the next update.
+func removeDefaultTags(tagsMap map[string]*string, configured map[string]interface{}, defaults map[string]interface{}) map[string]interface{} {
+	output := make(map[string]interface{}, len(tagsMap))
+
+	for k, v := range tagsMap {
+		if v == nil {
+			continue
+		}
+
+		if _, ok := configured[k]; !ok {
+			if d, ok := defaults[k]; ok {
+				if value, err := tagValueToString(d); err == nil && value == *v {
+					continue
+				}
+			}
+		}
+
+		output[k] = *v
+	}
+
+	for k := range defaults {
+		if _, ok := configured[k]; ok {
+			continue
+		}
+
+		if v, ok := tagsMap[k]; !ok || v == nil {
+			output[k] = ""
+		}
+	}
+
+	return output
+}
+
+// removeHiddenTags removes the tags whose key starts with the prefix and which weren't set on the resource,
+// since they're added (and relied upon) by Azure. An empty prefix doesn't remove any tags.
+func removeHiddenTags(tagsMap map[string]interface{}, configured map[string]interface{}, prefix string) map[string]interface{} {
+	output := make(map[string]interface{}, len(tagsMap))
+
+	for k, v := range tagsMap {
+		if _, ok := configured[k]; !ok && isHiddenTag(k, prefix) {
+			continue
+		}
+
+		output[k] = v
+	}
+
+	return output
+}
+
+// mergeHiddenTags returns the tags combined with the hidden tags of the existing resource, so that
+// updating the tags doesn't remove those added by Azure
+func mergeHiddenTags(tagsMap map[string]interface{}, existing *map[string]*string, prefix string) map[string]interface{} {
+	output := make(map[string]interface{}, len(tagsMap))
+
+	if existing != nil {
+		for k, v := range *existing {
+			if v != nil && isHiddenTag(k, prefix) {
+				output[k] = *v
+			}
+		}
+	}
+
+	for k, v := range tagsMap {
+		output[k] = v
+	}
+
+	return output
+}
+
+// isHiddenTag returns whether the tag key starts with the prefix - tag keys are case-insensitive in Azure
+func isHiddenTag(key string, prefix string) bool {
+	return prefix != "" && strings.HasPrefix(strings.ToLower(key), strings.ToLower(prefix))
+}
+
 func flattenAndSetTags(d *schema.ResourceData, tagsMap *map[string]*string) {
 	if tagsMap == nil {
 		d.Set("tags", make(map[string]interface{}))
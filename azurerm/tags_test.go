@@ -2,8 +2,12 @@ package azurerm
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/utils"
 )
 
 func TestValidateMaximumNumberOfARMTags(t *testing.T) {
@@ -68,6 +72,211 @@ func TestValidateARMTagMaxValueLength(t *testing.T) {
 	}
 }
 
+func TestValidateARMTagLengthBoundaries(t *testing.T) {
+	tagsMap := make(map[string]interface{})
+	tagsMap[strings.Repeat("k", 512)] = "value"
+	tagsMap["key"] = strings.Repeat("v", 256)
+
+	_, es := validateAzureRMTags(tagsMap, "tags")
+	if len(es) != 0 {
+		t.Fatalf("Expected no validation errors for keys of 512 chars and values of 256 chars: %+v", es)
+	}
+}
+
+func TestValidateARMTagReservedKeys(t *testing.T) {
+	cases := []struct {
+		Key       string
+		WarnCount int
+	}{
+		{
+			Key:       "environment",
+			WarnCount: 0,
+		},
+		{
+			Key:       "my-azure-tag",
+			WarnCount: 0,
+		},
+		{
+			Key:       "azure-tag",
+			WarnCount: 1,
+		},
+		{
+			Key:       "Microsoft.Tag",
+			WarnCount: 1,
+		},
+		{
+			Key:       "WindowsVersion",
+			WarnCount: 1,
+		},
+		{
+			Key:       "cost/center",
+			WarnCount: 1,
+		},
+		{
+			Key:       "a<b",
+			WarnCount: 1,
+		},
+		{
+			Key:       "a%b",
+			WarnCount: 1,
+		},
+	}
+
+	for _, tc := range cases {
+		tagsMap := map[string]interface{}{
+			tc.Key: "value",
+		}
+
+		ws, es := validateAzureRMTags(tagsMap, "tags")
+		if len(es) != 0 {
+			t.Fatalf("Expected no validation errors for the tag key %q - got %d: %+v", tc.Key, len(es), es)
+		}
+		if len(ws) != tc.WarnCount {
+			t.Fatalf("Expected %d validation warnings for the tag key %q - got %d: %+v", tc.WarnCount, tc.Key, len(ws), ws)
+		}
+	}
+}
+
+func TestMergeDefaultARMTags(t *testing.T) {
+	defaults := map[string]interface{}{
+		"cost-center": "1234",
+		"owner":       "platform",
+	}
+	tags := map[string]interface{}{
+		"owner":       "networking",
+		"environment": "production",
+	}
+
+	merged := mergeDefaultTags(defaults, tags)
+
+	expected := map[string]string{
+		"cost-center": "1234",
+		"owner":       "networking",
+		"environment": "production",
+	}
+	if len(merged) != len(expected) {
+		t.Fatalf("Expected %d merged tags but got %d: %+v", len(expected), len(merged), merged)
+	}
+	for k, v := range expected {
+		if merged[k] != v {
+			t.Fatalf("Expected the merged tag %q to be %q but got %q", k, v, merged[k])
+		}
+	}
+
+	if defaults["owner"] != "platform" {
+		t.Fatalf("Expected the default tags not to be modified")
+	}
+}
+
+func TestRemoveDefaultARMTags(t *testing.T) {
+	defaults := map[string]interface{}{
+		"cost-center": "1234",
+		"owner":       "platform",
+		"team":        "core",
+		"region":      "westeurope",
+	}
+	configured := map[string]interface{}{
+		"team": "core",
+	}
+	tags := map[string]*string{
+		"cost-center": utils.String("1234"),
+		"owner":       utils.String("networking"),
+		"team":        utils.String("core"),
+		"environment": utils.String("production"),
+	}
+
+	actual := removeDefaultTags(tags, configured, defaults)
+
+	// the `cost-center` matches the default so is removed, `owner` has been changed from the default
+	// (e.g. outside of Terraform) so is kept, `team` is kept since it's set on the resource too - and
+	// `region` has been added to the defaults since the resource was last updated, so is shown as empty
+	expected := map[string]interface{}{
+		"owner":       "networking",
+		"team":        "core",
+		"environment": "production",
+		"region":      "",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %+v but got %+v", expected, actual)
+	}
+}
+
+func TestRemoveHiddenARMTags(t *testing.T) {
+	configured := map[string]interface{}{
+		"hidden-title": "My Web Test",
+	}
+	tags := map[string]interface{}{
+		"environment":                     "production",
+		"hidden-title":                    "My Web Test",
+		"hidden-link:/subscriptions/1":    "Resource",
+		"Hidden-Related:/subscriptions/1": "Resource",
+		"not-hidden":                      "value",
+	}
+
+	// hidden tags are removed unless they're set on the resource, regardless of the casing of the prefix
+	expected := map[string]interface{}{
+		"environment":  "production",
+		"hidden-title": "My Web Test",
+		"not-hidden":   "value",
+	}
+	if actual := removeHiddenTags(tags, configured, defaultHiddenTagPrefix); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %+v but got %+v", expected, actual)
+	}
+
+	// an empty prefix disables the filtering
+	if actual := removeHiddenTags(tags, configured, ""); !reflect.DeepEqual(actual, tags) {
+		t.Fatalf("Expected %+v but got %+v", tags, actual)
+	}
+}
+
+func TestMergeHiddenARMTags(t *testing.T) {
+	existing := map[string]*string{
+		"environment":                  utils.String("staging"),
+		"hidden-link:/subscriptions/1": utils.String("Resource"),
+		"hidden-title":                 utils.String("Old Title"),
+	}
+	tags := map[string]interface{}{
+		"environment":  "production",
+		"hidden-title": "New Title",
+	}
+
+	// only the existing hidden tags are kept, and tags set on the resource take precedence
+	expected := map[string]interface{}{
+		"environment":                  "production",
+		"hidden-link:/subscriptions/1": "Resource",
+		"hidden-title":                 "New Title",
+	}
+	if actual := mergeHiddenTags(tags, &existing, defaultHiddenTagPrefix); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %+v but got %+v", expected, actual)
+	}
+
+	if actual := mergeHiddenTags(tags, nil, defaultHiddenTagPrefix); !reflect.DeepEqual(actual, tags) {
+		t.Fatalf("Expected %+v but got %+v", tags, actual)
+	}
+}
+
+func TestFlattenAndSetARMTagsWithDefaultsIgnoresHiddenTags(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{"tags": tagsSchema()}, map[string]interface{}{
+		"tags": map[string]interface{}{
+			"environment": "production",
+		},
+	})
+
+	flattenAndSetTagsWithDefaults(d, &map[string]*string{
+		"environment":                  utils.String("production"),
+		"cost-center":                  utils.String("1234"),
+		"hidden-link:/subscriptions/1": utils.String("Resource"),
+	}, map[string]interface{}{}, defaultHiddenTagPrefix)
+
+	expected := map[string]interface{}{
+		"environment": "production",
+		"cost-center": "1234",
+	}
+	if actual := d.Get("tags").(map[string]interface{}); !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("Expected %+v but got %+v", expected, actual)
+	}
+}
+
 func TestExpandARMTags(t *testing.T) {
 	testData := make(map[string]interface{})
 	testData["key1"] = "value1"
@@ -0,0 +1,42 @@
+package azurerm
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/terraform-providers/terraform-provider-azurerm/azurerm/helpers/response"
+)
+
+// these are variables rather than constants so that the tests don't need to wait
+var (
+	throttledRequestInitialDelay = 5 * time.Second
+	throttledRequestMaxDelay     = 1 * time.Minute
+)
+
+// retryOn429 calls `fn` until it returns a response which isn't throttled (a 429 or 503), backing off
+// exponentially between attempts. Once the context is done the last response's error is returned.
+func retryOn429(ctx context.Context, fn func() (*http.Response, error)) error {
+	delay := throttledRequestInitialDelay
+
+	for {
+		resp, err := fn()
+		if err == nil || !response.WasThrottled(resp) {
+			return err
+		}
+
+		log.Printf("[DEBUG] Request was throttled (Status Code %d) - retrying in %s", resp.StatusCode, delay)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > throttledRequestMaxDelay {
+			delay = throttledRequestMaxDelay
+		}
+	}
+}
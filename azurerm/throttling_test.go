@@ -0,0 +1,76 @@
+package azurerm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryOn429_succeedsAfterThrottling(t *testing.T) {
+	defer setThrottledRequestDelays(time.Millisecond)()
+
+	attempts := 0
+	err := retryOn429(context.Background(), func() (*http.Response, error) {
+		attempts++
+		if attempts <= 2 {
+			return &http.Response{StatusCode: http.StatusTooManyRequests}, fmt.Errorf("throttled")
+		}
+
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error but got: %+v", err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("Expected 3 attempts but got %d", attempts)
+	}
+}
+
+func TestRetryOn429_doesNotRetryOtherErrors(t *testing.T) {
+	defer setThrottledRequestDelays(time.Millisecond)()
+
+	statusCodes := []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError}
+	for _, statusCode := range statusCodes {
+		attempts := 0
+		err := retryOn429(context.Background(), func() (*http.Response, error) {
+			attempts++
+			return &http.Response{StatusCode: statusCode}, fmt.Errorf("failed")
+		})
+
+		if err == nil {
+			t.Fatalf("Expected an error for status code %d but didn't get one", statusCode)
+		}
+
+		if attempts != 1 {
+			t.Fatalf("Expected 1 attempt for status code %d but got %d", statusCode, attempts)
+		}
+	}
+}
+
+func TestRetryOn429_stopsWhenContextIsDone(t *testing.T) {
+	defer setThrottledRequestDelays(time.Millisecond)()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := retryOn429(ctx, func() (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable}, fmt.Errorf("unavailable")
+	})
+
+	if err == nil {
+		t.Fatalf("Expected an error once the context was done but didn't get one")
+	}
+}
+
+func setThrottledRequestDelays(delay time.Duration) func() {
+	initialDelay, maxDelay := throttledRequestInitialDelay, throttledRequestMaxDelay
+	throttledRequestInitialDelay, throttledRequestMaxDelay = delay, delay
+
+	return func() {
+		throttledRequestInitialDelay, throttledRequestMaxDelay = initialDelay, maxDelay
+	}
+}
@@ -1,7 +1,9 @@
 package azurerm
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net/url"
 	"regexp"
 	"time"
 
@@ -74,3 +76,43 @@ func validateStringLength(maxLength int) schema.SchemaValidateFunc {
 		return
 	}
 }
+
+func validateAbsoluteURL(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	u, err := url.ParseRequestURI(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("%q must be an absolute URL: %+v", k, err))
+		return
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		errors = append(errors, fmt.Errorf("%q must use either the `http` or `https` scheme, got %q", k, u.Scheme))
+		return
+	}
+
+	if u.Host == "" {
+		errors = append(errors, fmt.Errorf("%q must contain a host, got %q", k, value))
+	}
+
+	return
+}
+
+// validateStorageAccountAccessKey checks the value looks like a Storage Account Access Key, which is a base64
+// encoded 512-bit key. Azure doesn't validate the key itself, so a mismatch is only a warning - and since
+// the value is sensitive, it's never included in the warnings.
+func validateStorageAccountAccessKey(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		ws = append(ws, fmt.Sprintf("%q doesn't look like a Storage Account Access Key, which is base64 encoded", k))
+		return
+	}
+
+	if len(decoded) != 64 {
+		ws = append(ws, fmt.Sprintf("%q doesn't look like a Storage Account Access Key, which is 512 bits - got a %d-bit value", k, len(decoded)*8))
+	}
+
+	return
+}
@@ -1,6 +1,8 @@
 package azurerm
 
 import (
+	"encoding/base64"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/acctest"
@@ -130,3 +132,85 @@ func TestDBAccountName_validation(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateAbsoluteURL(t *testing.T) {
+	cases := []struct {
+		Value    string
+		ErrCount int
+	}{
+		{
+			Value:    "",
+			ErrCount: 1,
+		},
+		{
+			Value:    "/relative/path",
+			ErrCount: 1,
+		},
+		{
+			Value:    "ftp://example.com",
+			ErrCount: 1,
+		},
+		{
+			Value:    "https://",
+			ErrCount: 1,
+		},
+		{
+			Value:    "http://example.com",
+			ErrCount: 0,
+		},
+		{
+			Value:    "https://example.com:8080/webhook?token=abc",
+			ErrCount: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		_, errors := validateAbsoluteURL(tc.Value, "example")
+
+		if len(errors) != tc.ErrCount {
+			t.Fatalf("Expected validateAbsoluteURL to trigger '%d' errors for '%s' - got '%d'", tc.ErrCount, tc.Value, len(errors))
+		}
+	}
+}
+
+func TestValidateStorageAccountAccessKey(t *testing.T) {
+	cases := []struct {
+		Value     string
+		WarnCount int
+	}{
+		{
+			Value:     "",
+			WarnCount: 1,
+		},
+		{
+			Value:     "not base64!",
+			WarnCount: 1,
+		},
+		{
+			Value:     base64.StdEncoding.EncodeToString([]byte("too-short")),
+			WarnCount: 1,
+		},
+		{
+			Value:     base64.StdEncoding.EncodeToString(make([]byte, 64)),
+			WarnCount: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		ws, errors := validateStorageAccountAccessKey(tc.Value, "access_key")
+
+		if len(errors) != 0 {
+			t.Fatalf("Expected validateStorageAccountAccessKey not to trigger any errors for '%s' - got '%d'", tc.Value, len(errors))
+		}
+
+		if len(ws) != tc.WarnCount {
+			t.Fatalf("Expected validateStorageAccountAccessKey to trigger '%d' warnings for '%s' - got '%d'", tc.WarnCount, tc.Value, len(ws))
+		}
+
+		for _, warning := range ws {
+			if tc.Value != "" && strings.Contains(warning, tc.Value) {
+				t.Fatalf("Expected the validation warning not to contain the access key: %s", warning)
+			}
+		}
+	}
+}